@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the Advent of Code website
+const DefaultBaseURL = "https://adventofcode.com"
+
+// SessionEnv is the environment variable read for the session cookie before falling back to the config file
+const SessionEnv = "AOC_SESSION"
+
+// Client talks to the Advent of Code website, or anything that serves the same paths
+type Client struct {
+	BaseURL    string
+	Year       int
+	Session    string       // Value of the "session" cookie of a logged in browser
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+
+	// MinInterval is the least time allowed between two requests. When StateFile is set, its modification
+	// time records the last request so the limit also holds across separate runs.
+	MinInterval time.Duration
+	StateFile   string
+
+	lastRequest time.Time
+}
+
+// New returns a client for a year's puzzles on the real website
+func New(year int, session string) *Client {
+	return &Client{BaseURL: DefaultBaseURL, Year: year, Session: session, MinInterval: 5 * time.Second}
+}
+
+// LoadSession returns the session cookie from SessionEnv if it is set, otherwise from the first line of the
+// config file
+func LoadSession(configPath string) (string, error) {
+	if session := strings.TrimSpace(os.Getenv(SessionEnv)); session != "" {
+		return session, nil
+	}
+	data, err := os.ReadFile(configPath) // #nosec G304
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("no session cookie: set %s or write it to %s", SessionEnv, configPath)
+		}
+		return "", err
+	}
+	session, _, _ := strings.Cut(string(data), "\n")
+	if session = strings.TrimSpace(session); session == "" {
+		return "", fmt.Errorf("session file %s is empty", configPath)
+	}
+	return session, nil
+}
+
+// DefaultConfigDir is where the session cookie and rate limit state are kept, e.g. ~/.config/aoc
+func DefaultConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc"), nil
+}
+
+// FetchInput saves a day's puzzle input to path, unless path already exists. Inputs never change,
+// so a saved input is never fetched again. It reports whether the input was downloaded.
+func (c *Client) FetchInput(ctx context.Context, day int, path string) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	body, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/%d/day/%d/input", c.Year, day), nil)
+	if err != nil {
+		return false, fmt.Errorf("fetching day %d input: %v", day, err)
+	}
+	if err := writeFileAtomic(path, body); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// do sends an authenticated request once the rate limit allows it and returns the response body,
+// or an error for any status other than 200 OK
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader) ([]byte, error) {
+	if c.Session == "" {
+		return nil, errors.New("no session cookie")
+	}
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	req.Header.Set("User-Agent", "advent-of-code-2024 aoc tool (Go)")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	c.recordRequest()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// wait sleeps until MinInterval has passed since the last request
+func (c *Client) wait(ctx context.Context) error {
+	last := c.lastRequest
+	if c.StateFile != "" {
+		if info, err := os.Stat(c.StateFile); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	delay := time.Until(last.Add(c.MinInterval))
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordRequest notes the time of a request for the rate limit. Failing to update the state file
+// only weakens the limit across runs, so it isn't an error.
+func (c *Client) recordRequest() {
+	c.lastRequest = time.Now()
+	if c.StateFile == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.StateFile), 0o700); err != nil {
+		return
+	}
+	// A new file gets the filesystem's coarse clock as its modification time, so set it afterwards too
+	if err := os.Chtimes(c.StateFile, c.lastRequest, c.lastRequest); errors.Is(err, os.ErrNotExist) {
+		if os.WriteFile(c.StateFile, nil, 0o600) == nil {
+			_ = os.Chtimes(c.StateFile, c.lastRequest, c.lastRequest)
+		}
+	}
+}
+
+// writeFileAtomic writes through a temporary file so an interrupted download never leaves a partial input
+// that would then be treated as cached
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"advent-of-code-2024/client/fakeserver"
+)
+
+const (
+	testYear    = 2024
+	testSession = "test-session"
+)
+
+// newServer starts a fake website with two puzzles, closed when the test ends
+func newServer(t *testing.T) *fakeserver.Server {
+	t.Helper()
+	server := fakeserver.New(testYear, testSession, map[int]fakeserver.Puzzle{
+		1: {Input: "3   4\n4   3\n", Answers: [2]string{"11", "31"}},
+		2: {Input: "7 6 4 2 1\n", Answers: [2]string{"2", "4"}},
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newClient returns a client for the fake website with no rate limit
+func newClient(server *fakeserver.Server) *Client {
+	c := New(testYear, testSession)
+	c.BaseURL = server.URL
+	c.MinInterval = 0
+	return c
+}
+
+func TestFetchInput(t *testing.T) {
+	server := newServer(t)
+	path := filepath.Join(t.TempDir(), "input.txt")
+
+	downloaded, err := newClient(server).FetchInput(context.Background(), 1, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !downloaded {
+		t.Error("got downloaded=false for a new input")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "3   4\n4   3\n" {
+		t.Errorf("saved %q, want the puzzle input", data)
+	}
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Path != "/2024/day/1/input" {
+		t.Errorf("got requests %+v, want one for /2024/day/1/input", requests)
+	}
+}
+
+func TestFetchInputCached(t *testing.T) {
+	server := newServer(t)
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte("cached\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A bad session proves the client never gets as far as asking
+	c := newClient(server)
+	c.Session = "wrong"
+	downloaded, err := c.FetchInput(context.Background(), 1, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if downloaded {
+		t.Error("got downloaded=true for a cached input")
+	}
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Errorf("got requests %+v, want none", requests)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "cached\n" {
+		t.Errorf("cached input was overwritten with %q", data)
+	}
+}
+
+func TestFetchInputErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		session string
+		day     int
+		want    string // Part of the error
+	}{
+		{"bad session", "wrong", 1, "400"},
+		{"puzzle not unlocked", testSession, 25, "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t)
+			path := filepath.Join(t.TempDir(), "input.txt")
+			c := newClient(server)
+			c.Session = tt.session
+
+			downloaded, err := c.FetchInput(context.Background(), tt.day, path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.want)
+			}
+			if downloaded {
+				t.Error("got downloaded=true for a failed fetch")
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("a failed fetch left %s behind", path)
+			}
+		})
+	}
+}
+
+func TestFetchInputBaseURL(t *testing.T) {
+	for _, suffix := range []string{"", "/"} {
+		t.Run("suffix "+suffix, func(t *testing.T) {
+			server := newServer(t)
+			c := newClient(server)
+			c.BaseURL = server.URL + suffix
+
+			if _, err := c.FetchInput(context.Background(), 2, filepath.Join(t.TempDir(), "input.txt")); err != nil {
+				t.Fatal(err)
+			}
+			if requests := server.Requests(); len(requests) != 1 || requests[0].Path != "/2024/day/2/input" {
+				t.Errorf("got requests %+v, want one for /2024/day/2/input", requests)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	const interval = 200 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		stateFile  bool
+		sameClient bool
+	}{
+		{"same client", false, true},
+		{"separate clients sharing a state file", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t)
+			dir := t.TempDir()
+			newLimitedClient := func() *Client {
+				c := newClient(server)
+				c.MinInterval = interval
+				if tt.stateFile {
+					c.StateFile = filepath.Join(dir, "state", "last-request")
+				}
+				return c
+			}
+
+			c := newLimitedClient()
+			for day := 1; day <= 2; day++ {
+				if !tt.sameClient {
+					c = newLimitedClient()
+				}
+				if _, err := c.FetchInput(context.Background(), day, filepath.Join(dir, fmt.Sprintf("input%d.txt", day))); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			requests := server.Requests()
+			if len(requests) != 2 {
+				t.Fatalf("got %d requests, want 2", len(requests))
+			}
+			if gap := requests[1].Time.Sub(requests[0].Time); gap < interval {
+				t.Errorf("requests were %s apart, want at least %s", gap, interval)
+			}
+		})
+	}
+}
+
+func TestRateLimitCancelled(t *testing.T) {
+	server := newServer(t)
+	c := newClient(server)
+	c.MinInterval = time.Hour
+	c.StateFile = filepath.Join(t.TempDir(), "last-request")
+	if err := os.WriteFile(c.StateFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.FetchInput(ctx, 1, filepath.Join(t.TempDir(), "input.txt")); err == nil {
+		t.Fatal("fetched while the state file said to wait")
+	}
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Errorf("got requests %+v, want none", requests)
+	}
+}
@@ -0,0 +1,158 @@
+package fakeserver
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Puzzle is one day's puzzle as the server knows it
+type Puzzle struct {
+	Input   string
+	Answers [2]string // Correct answers to parts 1 and 2
+}
+
+// Server is a stand-in for the Advent of Code website, serving puzzle inputs to one session and judging
+// answers the way the real site does, so the client can be exercised offline
+type Server struct {
+	*httptest.Server
+	Session         string
+	WrongAnswerWait time.Duration // How long a wrong answer locks out further answers, one minute on the real site
+
+	mu          sync.Mutex
+	year        int
+	puzzles     map[int]Puzzle
+	solved      map[[2]int]bool
+	lockedUntil time.Time
+	requests    []Request
+}
+
+// Request is a request the server received
+type Request struct {
+	Method string
+	Path   string
+	Answer string // The submitted answer, for answer requests
+	Time   time.Time
+}
+
+// New starts a server for a year with the given puzzles by day. Close it when done.
+func New(year int, session string, puzzles map[int]Puzzle) *Server {
+	s := &Server{
+		Session:         session,
+		WrongAnswerWait: time.Minute,
+		year:            year,
+		puzzles:         puzzles,
+		solved:          make(map[[2]int]bool),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handle)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Requests returns every request received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	request := Request{Method: r.Method, Path: r.URL.Path, Time: time.Now()}
+	if r.Method == http.MethodPost {
+		request.Answer = r.PostFormValue("answer")
+	}
+	s.requests = append(s.requests, request)
+
+	var year, day int
+	var endpoint string
+	if _, err := fmt.Sscanf(r.URL.Path, "/%d/day/%d/%s", &year, &day, &endpoint); err != nil ||
+		r.URL.Path != fmt.Sprintf("/%d/day/%d/%s", year, day, endpoint) {
+		http.NotFound(w, r)
+		return
+	}
+	// The real site answers 400 without a valid session
+	if cookie, err := r.Cookie("session"); err != nil || cookie.Value != s.Session {
+		http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+		return
+	}
+	puzzle, ok := s.puzzles[day]
+	if year != s.year || !ok {
+		http.Error(w, "Please don't repeatedly request this endpoint before it unlocks! The calendar countdown is synchronized with the server time; the link will be enabled on the calendar the instant this puzzle becomes available.", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case endpoint == "input" && r.Method == http.MethodGet:
+		fmt.Fprint(w, puzzle.Input)
+	case endpoint == "answer" && r.Method == http.MethodPost:
+		s.answer(w, day, puzzle, r.PostFormValue("level"), request.Answer)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// answer judges a submitted answer, replying with a page shaped like the real site's
+func (s *Server) answer(w http.ResponseWriter, day int, puzzle Puzzle, level, answer string) {
+	reply := func(format string, a ...any) {
+		fmt.Fprintf(w, "<html><body><main>\n<article><p>%s [<a href=\"/%d/day/%d\">Return to Day %d</a>]</p></article>\n</main></body></html>\n",
+			fmt.Sprintf(format, a...), s.year, day, day)
+	}
+
+	part, err := strconv.Atoi(level)
+	if now := time.Now(); now.Before(s.lockedUntil) {
+		left := s.lockedUntil.Sub(now).Round(time.Second)
+		reply("You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have %s left to wait.", formatWait(left))
+		return
+	}
+	if err != nil || part < 1 || part > 2 || s.solved[[2]int{day, part}] || (part == 2 && !s.solved[[2]int{day, 1}]) {
+		reply("You don't seem to be solving the right level.  Did you already complete it?")
+		return
+	}
+
+	correct := puzzle.Answers[part-1]
+	if answer == correct {
+		s.solved[[2]int{day, part}] = true
+		reply("That's the right answer!  You are one gold star closer to finding the Chief Historian.")
+		return
+	}
+	s.lockedUntil = time.Now().Add(s.WrongAnswerWait)
+	hint := ""
+	if got, ok := new(big.Int).SetString(answer, 10); ok {
+		if want, ok := new(big.Int).SetString(correct, 10); ok {
+			hint = "your answer is too low.  "
+			if got.Cmp(want) > 0 {
+				hint = "your answer is too high.  "
+			}
+		}
+	}
+	reply("That's not the right answer; %sIf you're stuck, make sure you're using the full input data.%s", hint, waitSentence(s.WrongAnswerWait))
+}
+
+// waitSentence asks to wait after a wrong answer, in whole minutes like the real site, or says nothing
+// for waits under a minute
+func waitSentence(wait time.Duration) string {
+	switch minutes := int(wait / time.Minute); minutes {
+	case 0:
+		return ""
+	case 1:
+		return "  Please wait one minute before trying again."
+	default:
+		return fmt.Sprintf("  Please wait %d minutes before trying again.", minutes)
+	}
+}
+
+// formatWait writes a wait like the real site, e.g. "4m 32s" or "37s"
+func formatWait(d time.Duration) string {
+	minutes, seconds := int(d/time.Minute), int(d%time.Minute/time.Second)
+	if minutes > 0 {
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Attempt is one submitted answer and the website's verdict on it
+type Attempt struct {
+	Year    int           `json:"year"`
+	Day     int           `json:"day"`
+	Part    int           `json:"part"`
+	Answer  string        `json:"answer"`
+	Verdict Verdict       `json:"verdict"`
+	Wait    time.Duration `json:"wait,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Time    time.Time     `json:"time"`
+}
+
+// History is every answer submitted, kept in a JSON file
+type History struct {
+	path     string
+	Attempts []Attempt `json:"attempts"`
+}
+
+// LoadHistory reads the history file, or starts an empty history if it doesn't exist yet
+func LoadHistory(path string) (*History, error) {
+	h := &History{path: path}
+	data, err := os.ReadFile(path) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("reading answer history %s: %v", path, err)
+	}
+	return h, nil
+}
+
+// Record adds an attempt and saves the history
+func (h *History) Record(attempt Attempt) error {
+	h.Attempts = append(h.Attempts, attempt)
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return err
+	}
+	return writeFileAtomic(h.path, append(data, '\n'))
+}
+
+// Check returns an error explaining why an answer shouldn't be submitted, based on earlier attempts:
+// the site asked to wait and the time isn't up yet, the part is already solved, the answer was already
+// rejected, or the answer is beyond a known too high or too low answer
+func (h *History) Check(year, day, part int, answer string, now time.Time) error {
+	for _, attempt := range h.Attempts {
+		// Waits apply to every puzzle, not just the one answered
+		if until := attempt.Time.Add(attempt.Wait); attempt.Wait > 0 && now.Before(until) {
+			return fmt.Errorf("the site asked to wait before answering again, try after %s", until.Format(time.TimeOnly))
+		}
+		if attempt.Year != year || attempt.Day != day || attempt.Part != part {
+			continue
+		}
+		cmp, numeric := compareNumbers(answer, attempt.Answer)
+		switch {
+		case attempt.Verdict == Correct:
+			return fmt.Errorf("day %d part %d is already solved with %s", day, part, attempt.Answer)
+		case attempt.Verdict.IsWrong() && attempt.Answer == answer:
+			return fmt.Errorf("%s was already submitted for day %d part %d and was %s", answer, day, part, attempt.Verdict)
+		case attempt.Verdict == TooHigh && numeric && cmp >= 0:
+			return fmt.Errorf("%s can't be right, %s was already too high", answer, attempt.Answer)
+		case attempt.Verdict == TooLow && numeric && cmp <= 0:
+			return fmt.Errorf("%s can't be right, %s was already too low", answer, attempt.Answer)
+		}
+	}
+	return nil
+}
+
+// compareNumbers compares two integer answers like strings.Compare. ok is false if either isn't an integer.
+func compareNumbers(a, b string) (cmp int, ok bool) {
+	x, okA := new(big.Int).SetString(a, 10)
+	y, okB := new(big.Int).SetString(b, 10)
+	if !okA || !okB {
+		return 0, false
+	}
+	return x.Cmp(y), true
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistoryCheck(t *testing.T) {
+	now := time.Date(2024, 12, 1, 6, 0, 0, 0, time.UTC)
+	attempt := func(day, part int, answer string, verdict Verdict) Attempt {
+		return Attempt{Year: testYear, Day: day, Part: part, Answer: answer, Verdict: verdict, Time: now.Add(-time.Hour)}
+	}
+	waited := func(a Attempt, ago, wait time.Duration) Attempt {
+		a.Time, a.Wait = now.Add(-ago), wait
+		return a
+	}
+
+	tests := []struct {
+		name     string
+		attempts []Attempt
+		answer   string
+		wantErr  bool
+	}{
+		{"no history", nil, "42", false},
+		{"known wrong answer", []Attempt{attempt(1, 1, "42", Wrong)}, "42", true},
+		{"another wrong answer", []Attempt{attempt(1, 1, "42", Wrong)}, "43", false},
+		{"known too high answer", []Attempt{attempt(1, 1, "100", TooHigh)}, "100", true},
+		{"above a too high answer", []Attempt{attempt(1, 1, "100", TooHigh)}, "150", true},
+		{"below a too high answer", []Attempt{attempt(1, 1, "100", TooHigh)}, "99", false},
+		{"below a too low answer", []Attempt{attempt(1, 1, "10", TooLow)}, "9", true},
+		{"above a too low answer", []Attempt{attempt(1, 1, "10", TooLow)}, "11", false},
+		{"between the bounds", []Attempt{attempt(1, 1, "10", TooLow), attempt(1, 1, "100", TooHigh)}, "50", false},
+		{"bounds beyond int64", []Attempt{attempt(1, 1, "99999999999999999999", TooHigh)}, "100000000000000000000", true},
+		{"not a number", []Attempt{attempt(1, 1, "100", TooHigh)}, "abc", false},
+		{"already solved", []Attempt{attempt(1, 1, "42", Correct)}, "43", true},
+		{"bound for another part", []Attempt{attempt(1, 2, "100", TooHigh)}, "150", false},
+		{"bound for another day", []Attempt{attempt(2, 1, "100", TooHigh)}, "150", false},
+		{"during a wait", []Attempt{waited(attempt(1, 1, "42", Wrong), 30*time.Second, time.Minute)}, "43", true},
+		{"during a wait for another puzzle", []Attempt{waited(attempt(2, 1, "42", Wrong), 30*time.Second, time.Minute)}, "43", true},
+		{"after a wait", []Attempt{waited(attempt(1, 1, "42", Wrong), 2*time.Minute, time.Minute)}, "43", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &History{Attempts: tt.attempts}
+			err := h.Check(testYear, 1, 1, tt.answer, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aoc", "history.json")
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Attempts) != 0 {
+		t.Fatalf("got %d attempts from a missing file, want none", len(h.Attempts))
+	}
+
+	when := time.Date(2024, 12, 1, 5, 0, 0, 0, time.UTC)
+	attempts := []Attempt{
+		{Year: testYear, Day: 1, Part: 1, Answer: "12", Verdict: TooHigh, Wait: time.Minute, Message: "That's not the right answer", Time: when},
+		{Year: testYear, Day: 1, Part: 1, Answer: "11", Verdict: Correct, Time: when.Add(2 * time.Minute)},
+	}
+	for _, attempt := range attempts {
+		if err := h.Record(attempt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reloaded.Attempts, attempts) {
+		t.Errorf("reloaded %+v, want %+v", reloaded.Attempts, attempts)
+	}
+	if err := reloaded.Check(testYear, 1, 1, "11", when.Add(time.Hour)); err == nil {
+		t.Error("reloaded history allowed answering a solved part")
+	}
+}
+
+// TestHistoryAgainstServer records the server's verdicts the way aoc submit does and checks they stop
+// answers that can't be right from being sent
+func TestHistoryAgainstServer(t *testing.T) {
+	server := newServer(t)
+	server.WrongAnswerWait = time.Minute
+	c := newClient(server)
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	submit := func(answer string) {
+		t.Helper()
+		h, err := LoadHistory(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response, err := c.Submit(context.Background(), 1, 1, answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attempt := Attempt{Year: testYear, Day: 1, Part: 1, Answer: answer, Verdict: response.Verdict,
+			Wait: response.Wait, Message: response.Message, Time: time.Now()}
+		if err := h.Record(attempt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(answer string, now time.Time) error {
+		t.Helper()
+		h, err := LoadHistory(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h.Check(testYear, 1, 1, answer, now)
+	}
+
+	submit("20")
+	if err := check("15", time.Now()); err == nil {
+		t.Error("allowed an answer during the wait the server asked for")
+	}
+	later := time.Now().Add(2 * time.Minute)
+	if err := check("25", later); err == nil {
+		t.Error("allowed an answer above one the server said was too high")
+	}
+	if err := check("20", later); err == nil {
+		t.Error("allowed an answer the server already rejected")
+	}
+	if err := check("15", later); err != nil {
+		t.Errorf("refused an answer below the too high one: %v", err)
+	}
+	if requests := server.Requests(); len(requests) != 1 {
+		t.Errorf("got %d requests, want only the one submission", len(requests))
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Verdict is the website's judgement of a submitted answer
+type Verdict string
+
+const (
+	Correct       Verdict = "correct"
+	Wrong         Verdict = "wrong"
+	TooHigh       Verdict = "too high"
+	TooLow        Verdict = "too low"
+	RateLimited   Verdict = "rate limited"   // An answer was sent too recently, nothing was checked
+	AlreadySolved Verdict = "already solved" // The part is already solved, or not unlocked yet
+	Unknown       Verdict = "unknown"        // The response wasn't recognised
+)
+
+// IsWrong reports whether the answer was checked and rejected
+func (v Verdict) IsWrong() bool {
+	return v == Wrong || v == TooHigh || v == TooLow
+}
+
+// Response is the parsed reply to a submitted answer
+type Response struct {
+	Verdict Verdict
+	Wait    time.Duration // How long until another answer may be submitted, if the site said
+	Message string        // The text of the reply, without HTML
+}
+
+var (
+	articlePattern = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagPattern     = regexp.MustCompile(`<[^>]*>`)
+	spacePattern   = regexp.MustCompile(`\s+`)
+	returnPattern  = regexp.MustCompile(`\[Return to [^\]]*\]`)
+	leftPattern    = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+	waitPattern    = regexp.MustCompile(`[Pp]lease wait (one|\d+) minutes? before trying again`)
+)
+
+// Submit posts an answer for a part of a day's puzzle and parses the reply
+func (c *Client) Submit(ctx context.Context, day, part int, answer string) (Response, error) {
+	form := url.Values{"level": {strconv.Itoa(part)}, "answer": {answer}}
+	body, err := c.do(ctx, "POST", fmt.Sprintf("/%d/day/%d/answer", c.Year, day), strings.NewReader(form.Encode()))
+	if err != nil {
+		return Response{}, fmt.Errorf("submitting day %d part %d: %v", day, part, err)
+	}
+	return ParseResponse(string(body)), nil
+}
+
+// ParseResponse reads the verdict and any wait time out of the HTML page returned for a submitted answer
+func ParseResponse(page string) Response {
+	text := page
+	if match := articlePattern.FindStringSubmatch(page); match != nil {
+		text = match[1]
+	}
+	text = html.UnescapeString(tagPattern.ReplaceAllString(text, ""))
+	text = returnPattern.ReplaceAllString(text, "")
+	text = strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+
+	response := Response{Verdict: Unknown, Message: text}
+	switch {
+	case strings.Contains(text, "That's the right answer"):
+		response.Verdict = Correct
+	case strings.Contains(text, "That's not the right answer"):
+		response.Verdict = Wrong
+		if strings.Contains(text, "your answer is too high") {
+			response.Verdict = TooHigh
+		} else if strings.Contains(text, "your answer is too low") {
+			response.Verdict = TooLow
+		}
+	case strings.Contains(text, "You gave an answer too recently"):
+		response.Verdict = RateLimited
+	case strings.Contains(text, "You don't seem to be solving the right level"):
+		response.Verdict = AlreadySolved
+	}
+
+	if match := leftPattern.FindStringSubmatch(text); match != nil {
+		minutes, _ := strconv.Atoi(match[1])
+		seconds, _ := strconv.Atoi(match[2])
+		response.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	} else if match := waitPattern.FindStringSubmatch(text); match != nil {
+		minutes := 1
+		if match[1] != "one" {
+			minutes, _ = strconv.Atoi(match[1])
+		}
+		response.Wait = time.Duration(minutes) * time.Minute
+	}
+	return response
+}
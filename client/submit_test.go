@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSubmit(t *testing.T) {
+	tests := []struct {
+		name      string
+		wait      time.Duration // How long the server locks out answers after a wrong one
+		before    []string      // Answers to day 1 part 1 submitted first
+		answer    string
+		want      Verdict
+		wantWait  time.Duration
+		tolerance time.Duration // How far the parsed wait may be below wantWait
+	}{
+		{name: "correct", answer: "11", want: Correct},
+		{name: "wrong", answer: "eleven", want: Wrong},
+		{name: "too high", answer: "12", want: TooHigh},
+		{name: "too low", answer: "10", want: TooLow},
+		{name: "wrong with a wait", wait: 5 * time.Minute, answer: "12", want: TooHigh, wantWait: 5 * time.Minute},
+		{name: "rate limited", wait: 5 * time.Minute, before: []string{"12"}, answer: "11", want: RateLimited,
+			wantWait: 5 * time.Minute, tolerance: 2 * time.Second},
+		{name: "rate limited under a minute", wait: 30 * time.Second, before: []string{"12"}, answer: "11", want: RateLimited,
+			wantWait: 30 * time.Second, tolerance: 2 * time.Second},
+		{name: "already solved", before: []string{"11"}, answer: "11", want: AlreadySolved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t)
+			server.WrongAnswerWait = tt.wait
+			c := newClient(server)
+
+			for _, answer := range tt.before {
+				if _, err := c.Submit(context.Background(), 1, 1, answer); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := c.Submit(context.Background(), 1, 1, tt.answer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Verdict != tt.want {
+				t.Errorf("got verdict %q, want %q (message %q)", got.Verdict, tt.want, got.Message)
+			}
+			if got.Wait > tt.wantWait || got.Wait < tt.wantWait-tt.tolerance {
+				t.Errorf("got wait %s, want %s", got.Wait, tt.wantWait)
+			}
+
+			requests := server.Requests()
+			last := requests[len(requests)-1]
+			if last.Method != "POST" || last.Path != "/2024/day/1/answer" || last.Answer != tt.answer {
+				t.Errorf("got request %+v, want %s posted to /2024/day/1/answer", last, tt.answer)
+			}
+		})
+	}
+}
+
+func TestSubmitBadSession(t *testing.T) {
+	server := newServer(t)
+	c := newClient(server)
+	c.Session = "wrong"
+	if _, err := c.Submit(context.Background(), 1, 1, "11"); err == nil {
+		t.Fatal("submitted with a bad session")
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		want     Verdict
+		wantWait time.Duration
+	}{
+		{
+			name:     "too low with the real site's markup",
+			page:     `<main><article><p>That's not the right answer; your answer is too low.  If you're stuck, make sure you're using the full input data; there are also some general tips on the <a href="/2024/about">about page</a>, or you can ask for hints on the <a href="https://www.reddit.com/r/adventofcode/" target="_blank">subreddit</a>.  Please wait one minute before trying again. <a href="/2024/day/1">[Return to Day 1]</a></p></article></main>`,
+			want:     TooLow,
+			wantWait: time.Minute,
+		},
+		{
+			name:     "longer wait after repeated wrong answers",
+			page:     `<article><p>That's not the right answer.  Please wait 5 minutes before trying again.</p></article>`,
+			want:     Wrong,
+			wantWait: 5 * time.Minute,
+		},
+		{
+			name:     "rate limited",
+			page:     `<article><p>You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 4m 32s left to wait. [<a href="/2024/day/1">Return to Day 1</a>]</p></article>`,
+			want:     RateLimited,
+			wantWait: 4*time.Minute + 32*time.Second,
+		},
+		{
+			name: "unrecognised",
+			page: `<html><body>Something else</body></html>`,
+			want: Unknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseResponse(tt.page)
+			if got.Verdict != tt.want || got.Wait != tt.wantWait {
+				t.Errorf("got %q with wait %s, want %q with wait %s", got.Verdict, got.Wait, tt.want, tt.wantWait)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"advent-of-code-2024/runner"
+)
+
+// runnerImport is the import path of the runner, which every day registering solvers imports
+const runnerImport = "advent-of-code-2024/runner"
+
+// registeredDays lists the days whose packages register solvers with the runner
+func registeredDays(root string) ([]int, error) {
+	cmd := exec.Command("go", "list", "-f", `{{.Name}} {{.Dir}} {{join .Imports " "}}`, "./...")
+	cmd.Dir = root
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing packages: %v", err)
+	}
+
+	var days []int
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "main" {
+			continue
+		}
+		var day int
+		if _, err := fmt.Sscanf(filepath.Base(fields[1]), "day%d", &day); err != nil || filepath.Base(fields[1]) != runner.DayDir(day) {
+			continue
+		}
+		for _, imported := range fields[2:] {
+			if imported == runnerImport {
+				days = append(days, day)
+				break
+			}
+		}
+	}
+	sort.Ints(days)
+	return days, nil
+}
+
+// buildDay compiles a day into dir and returns the path of the binary
+func buildDay(root, dir string, day int) (string, error) {
+	binary := filepath.Join(dir, runner.DayDir(day))
+	cmd := exec.Command("go", "build", "-o", binary, "./"+runner.DayDir(day))
+	cmd.Dir = root
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("building %s: %v\n%s", runner.DayDir(day), err, strings.TrimSpace(string(output)))
+	}
+	return binary, nil
+}
+
+// runPart runs one part of a day from the day's directory, using a built binary or go run if binary is empty,
+// giving the solver timeout to finish (no limit if zero). It returns the part's result, and an error if the part
+// failed, timed out or printed no result.
+func runPart(root, binary string, day, part int, timeout time.Duration) (runner.Result, error) {
+	args := []string{"-part", fmt.Sprint(part), "-format", "json", "-timeout", timeout.String()}
+	cmd := exec.Command(binary, args...)
+	if binary == "" {
+		cmd = exec.Command("go", append([]string{"run", "."}, args...)...)
+	}
+	cmd.Dir = filepath.Join(root, runner.DayDir(day))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	// A failing part still writes its result, with the error, so decode before checking how the run ended
+	runErr := cmd.Run()
+	var results []runner.Result
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil || len(results) != 1 {
+		result := runner.Result{Day: day, Part: part}
+		if runErr != nil {
+			return result, fmt.Errorf("%v: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+		return result, fmt.Errorf("%s printed no result for part %d", runner.DayDir(day), part)
+	}
+	result := results[0]
+	if result.Error != "" {
+		return result, errors.New(result.Error)
+	}
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"advent-of-code-2024/client"
+	"advent-of-code-2024/runner"
+)
+
+// year is the event this repository solves
+const year = 2024
+
+// clientOptions are the flags shared by the commands that talk to the website
+type clientOptions struct {
+	baseURL     string
+	sessionFile string
+	minInterval time.Duration
+	configDir   string
+}
+
+func addClientFlags(flags *flag.FlagSet) *clientOptions {
+	opts := &clientOptions{}
+	configDir, err := client.DefaultConfigDir()
+	if err != nil {
+		configDir = ".aoc"
+	}
+	opts.configDir = configDir
+
+	baseURL := os.Getenv("AOC_BASE_URL")
+	if baseURL == "" {
+		baseURL = client.DefaultBaseURL
+	}
+	flags.StringVar(&opts.baseURL, "base-url", baseURL, "website to talk to, also set by AOC_BASE_URL")
+	flags.StringVar(&opts.sessionFile, "session-file", filepath.Join(configDir, "session"),
+		"file holding the session cookie, used when "+client.SessionEnv+" is not set")
+	flags.DurationVar(&opts.minInterval, "rate", 5*time.Second, "least time between requests, including across runs")
+	return opts
+}
+
+func (opts *clientOptions) client() (*client.Client, error) {
+	session, err := client.LoadSession(opts.sessionFile)
+	if err != nil {
+		return nil, err
+	}
+	c := client.New(year, session)
+	c.BaseURL = opts.baseURL
+	c.MinInterval = opts.minInterval
+	c.StateFile = filepath.Join(opts.configDir, "last-request")
+	return c, nil
+}
+
+// runFetch downloads the inputs of one or more days into their directories, skipping inputs already there
+func runFetch(args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	opts := addClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("expected one or more days, e.g. 'aoc fetch 12'")
+	}
+	var days []int
+	for _, arg := range flags.Args() {
+		day, err := parseDay(arg)
+		if err != nil {
+			return err
+		}
+		days = append(days, day)
+	}
+
+	root, err := moduleRoot()
+	if err != nil {
+		return err
+	}
+	// The client is only built once an input is missing, so cached days need no session cookie
+	var c *client.Client
+	for _, day := range days {
+		dir := filepath.Join(root, runner.DayDir(day))
+		if _, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("%s does not exist, run 'aoc new %d' first", runner.DayDir(day), day)
+		}
+		path := filepath.Join(dir, "input.txt")
+		if _, err := os.Stat(path); err == nil {
+			fmt.Printf("%s/input.txt is already cached\n", runner.DayDir(day))
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		if c == nil {
+			if c, err = opts.client(); err != nil {
+				return err
+			}
+		}
+		downloaded, err := c.FetchInput(context.Background(), day, path)
+		if err != nil {
+			return err
+		}
+		if downloaded {
+			fmt.Printf("Downloaded %s/input.txt\n", runner.DayDir(day))
+		} else {
+			fmt.Printf("%s/input.txt is already cached\n", runner.DayDir(day))
+		}
+	}
+	return nil
+}
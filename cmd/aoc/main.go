@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// command is an aoc subcommand, given the arguments after its name
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"new", "create a day's package from the template", runNew},
+	{"fetch", "download days' puzzle inputs, unless already cached", runFetch},
+	{"submit", "submit a part's answer and record the verdict", runSubmit},
+	{"verify", "re-run every day and compare with the locked answers", runVerify},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: aoc <command> [flags] <arguments>")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'aoc <command> -h' for a command's flags.")
+}
+
+// parseDay parses a day number, which must be between 1 and 25
+func parseDay(s string) (int, error) {
+	day, err := strconv.Atoi(s)
+	if err != nil || day < 1 || day > 25 {
+		return 0, fmt.Errorf("invalid day '%s', expected a number from 1 to 25", s)
+	}
+	return day, nil
+}
+
+// moduleRoot finds the repository root by looking for go.mod in the working directory and its parents
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("not inside the repository: no go.mod found")
+		}
+		dir = parent
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "aoc %s: %v\n", c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "aoc: unknown command '%s'\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"advent-of-code-2024/runner"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// inputReader is a shared helper reader the generated parsing stub can start from
+type inputReader struct {
+	Type string // Type of the parsed input
+	Read string // Statement returning the parsed input and an error, reading from inputPath
+}
+
+var inputReaders = map[string]inputReader{
+	"grid":   {Type: "helper.Grid", Read: "return helper.ReadInputToGrid(inputPath), nil"},
+	"digits": {Type: "[][]int", Read: "return helper.ReadInputToInt2DArray(inputPath), nil"},
+	"text":   {Type: "string", Read: "return helper.ReadInputAsString(inputPath)"},
+}
+
+// scaffold is the data the templates are filled in with
+type scaffold struct {
+	Day    int
+	Reader inputReader
+}
+
+// generatedFiles maps each template to the file it generates in the day's directory
+var generatedFiles = map[string]string{
+	"main.go.tmpl":       "main.go",
+	"main_test.go.tmpl":  "main_test.go",
+	"bench_test.go.tmpl": "bench_test.go",
+}
+
+func readerNames() string {
+	var names []string
+	for name := range inputReaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// runNew creates dayNN/ with a registered solver, a parsing stub, example tests and benchmarks
+func runNew(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	readerName := flags.String("reader", "grid", "helper reader for the parsing stub: "+readerNames())
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one day, e.g. 'aoc new 12'")
+	}
+	day, err := parseDay(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	reader, ok := inputReaders[*readerName]
+	if !ok {
+		return fmt.Errorf("unknown reader '%s', expected one of %s", *readerName, readerNames())
+	}
+
+	root, err := moduleRoot()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(root, runner.DayDir(day))
+
+	// Render everything before touching the disk so a broken template never leaves a half-made day
+	files := make(map[string][]byte)
+	for templateName, fileName := range generatedFiles {
+		source, err := renderTemplate(templateName, scaffold{Day: day, Reader: reader})
+		if err != nil {
+			return err
+		}
+		files[fileName] = source
+	}
+
+	// Mkdir fails if the day already exists, so an existing day is never overwritten
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%s already exists, refusing to overwrite it", runner.DayDir(day))
+		}
+		return err
+	}
+	for fileName, source := range files {
+		if err := os.WriteFile(filepath.Join(dir, fileName), source, 0o644); err != nil { // #nosec G306
+			return err
+		}
+	}
+	fmt.Printf("Created %s with %d files. Add input.txt and the puzzle's example to get started.\n", runner.DayDir(day), len(files))
+	return nil
+}
+
+// renderTemplate fills in a template and gofmts the result
+func renderTemplate(name string, data scaffold) ([]byte, error) {
+	tmpl, err := template.ParseFS(templateFiles, "templates/"+name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("template %s: %v", name, err)
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("template %s generated invalid Go: %v", name, err)
+	}
+	return source, nil
+}
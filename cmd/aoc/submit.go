@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"advent-of-code-2024/client"
+	"advent-of-code-2024/runner"
+)
+
+// runSubmit computes a part's answer by running the day, or takes it from -answer, and submits it
+// unless the answer history shows it can't be right. Accepted answers are locked in for aoc verify.
+func runSubmit(args []string) error {
+	flags := flag.NewFlagSet("submit", flag.ContinueOnError)
+	opts := addClientFlags(flags)
+	answer := flags.String("answer", "", "answer to submit instead of running the day")
+	historyPath := flags.String("history", filepath.Join(opts.configDir, "history.json"), "JSON file recording every submitted answer")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New("expected a day and a part, e.g. 'aoc submit 12 1'")
+	}
+	day, err := parseDay(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	part, err := parsePart(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	if *answer == "" {
+		root, err := moduleRoot()
+		if err != nil {
+			return err
+		}
+		result, err := runPart(root, "", day, part, 0)
+		if err != nil {
+			return fmt.Errorf("running %s: %v", runner.DayDir(day), err)
+		}
+		*answer = result.Answer
+		fmt.Printf("Day %d Part %d answer: %s\n", day, part, *answer)
+	}
+
+	history, err := client.LoadHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	if err := history.Check(year, day, part, *answer, time.Now()); err != nil {
+		return fmt.Errorf("not submitting: %v", err)
+	}
+
+	c, err := opts.client()
+	if err != nil {
+		return err
+	}
+	response, err := c.Submit(context.Background(), day, part, *answer)
+	if err != nil {
+		return err
+	}
+	attempt := client.Attempt{
+		Year:    year,
+		Day:     day,
+		Part:    part,
+		Answer:  *answer,
+		Verdict: response.Verdict,
+		Wait:    response.Wait,
+		Message: response.Message,
+		Time:    time.Now(),
+	}
+	if err := history.Record(attempt); err != nil {
+		return fmt.Errorf("recording the attempt: %v", err)
+	}
+
+	fmt.Printf("%s: %s\n", response.Verdict, response.Message)
+	if response.Wait > 0 {
+		fmt.Printf("Wait %s before submitting again\n", response.Wait)
+	}
+	if response.Verdict != client.Correct {
+		return fmt.Errorf("%s was not accepted (%s)", *answer, response.Verdict)
+	}
+	return lockAnswer(day, part, *answer)
+}
+
+// lockAnswer records an accepted answer in the day's answers file, for aoc verify
+func lockAnswer(day, part int, answer string) error {
+	root, err := moduleRoot()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(root, runner.DayDir(day))
+	if _, err := os.Stat(dir); err != nil {
+		// The answer was given with -answer for a day that isn't in this repository
+		return nil
+	}
+	answers, err := runner.LoadAnswers(dir)
+	if err != nil {
+		return err
+	}
+	answers.Set(part, answer)
+	if err := runner.SaveAnswers(dir, answers); err != nil {
+		return err
+	}
+	fmt.Printf("Locked in as the answer in %s/%s\n", runner.DayDir(day), runner.AnswersFile)
+	return nil
+}
+
+// parsePart parses a part number, which must be 1 or 2
+func parsePart(s string) (int, error) {
+	switch s {
+	case "1":
+		return 1, nil
+	case "2":
+		return 2, nil
+	}
+	return 0, fmt.Errorf("invalid part '%s', expected 1 or 2", s)
+}
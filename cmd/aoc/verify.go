@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"advent-of-code-2024/runner"
+)
+
+// status is the outcome of checking one part against its locked answer
+type status string
+
+const (
+	pass     status = "pass"
+	fail     status = "FAIL"    // The answer differs from the locked one
+	broken   status = "ERROR"   // The part didn't run or printed no answer
+	timedOut status = "TIMEOUT" // The part didn't finish within -timeout
+	unlocked status = "-"       // There's no locked answer to compare with, so the part isn't run
+)
+
+// check is the result of running one part
+type check struct {
+	day, part int
+	status    status
+	got, want string
+	elapsed   time.Duration
+	err       error
+}
+
+// runVerify re-runs every registered day, or the days given, and compares each part with the answers locked
+// in its answers file. Parts without a locked answer are skipped. It fails if any part is wrong, doesn't run
+// or takes longer than -timeout.
+func runVerify(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	timeout := flags.Duration("timeout", time.Minute, "give up on a part after this long (0 for no limit)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	root, err := moduleRoot()
+	if err != nil {
+		return err
+	}
+
+	days, err := registeredDays(root)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		days = nil
+		for _, arg := range flags.Args() {
+			day, err := parseDay(arg)
+			if err != nil {
+				return err
+			}
+			days = append(days, day)
+		}
+		sort.Ints(days)
+	}
+
+	buildDir, err := os.MkdirTemp("", "aoc-verify")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(buildDir)
+
+	var checks []check
+	for _, day := range days {
+		checks = append(checks, verifyDay(root, buildDir, day, *timeout)...)
+	}
+	printMatrix(checks)
+
+	failures := 0
+	for _, c := range checks {
+		if c.status == fail || c.status == broken || c.status == timedOut {
+			failures++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d of %d parts failed", failures, len(checks))
+	}
+	return nil
+}
+
+// verifyDay builds a day and checks both its parts
+func verifyDay(root, buildDir string, day int, timeout time.Duration) []check {
+	checks := []check{{day: day, part: 1}, {day: day, part: 2}}
+	answers, err := runner.LoadAnswers(filepath.Join(root, runner.DayDir(day)))
+	binary := ""
+	if err == nil {
+		binary, err = buildDay(root, buildDir, day)
+	}
+	for i := range checks {
+		c := &checks[i]
+		if err != nil {
+			c.status, c.err = broken, err
+			continue
+		}
+		want, locked := answers.Get(c.part)
+		if !locked {
+			c.status = unlocked
+			continue
+		}
+		c.want = want
+		var result runner.Result
+		result, c.err = runPart(root, binary, day, c.part, timeout)
+		c.got, c.elapsed = result.Answer, result.Duration
+		switch {
+		case result.TimedOut:
+			c.status = timedOut
+		case c.err != nil:
+			c.status = broken
+		case c.got == want:
+			c.status = pass
+		default:
+			c.status = fail
+		}
+	}
+	return checks
+}
+
+// printMatrix prints a row per day with each part's status and time, then the details of every failure
+func printMatrix(checks []check) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Day\tPart 1\tTime\tPart 2\tTime")
+	for i := 0; i+1 < len(checks); i += 2 {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", checks[i].day,
+			checks[i].status, formatElapsed(checks[i]), checks[i+1].status, formatElapsed(checks[i+1]))
+	}
+	w.Flush()
+
+	for _, c := range checks {
+		switch c.status {
+		case fail:
+			fmt.Printf("Day %d Part %d: got %s, want %s\n", c.day, c.part, c.got, c.want)
+		case broken, timedOut:
+			fmt.Printf("Day %d Part %d: %v\n", c.day, c.part, c.err)
+		}
+	}
+}
+
+func formatElapsed(c check) string {
+	if c.elapsed == 0 {
+		return "-"
+	}
+	return c.elapsed.Round(time.Millisecond).String()
+}
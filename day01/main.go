@@ -1,38 +1,137 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/csv"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"sort"
 	"strconv"
+	"strings"
+
+	"advent-of-code-2024/helper"
+	"advent-of-code-2024/runner"
 )
 
-// ReadCsvFile reads a CSV file and returns a 2D slice of strings
-func readCsvFile(filePath string) [][]string {
-	f, err := os.Open(filePath) // #nosec G304
-	if err != nil {
-		log.Fatal("Unable to read input file "+filePath, err)
+// columnFormat is how the two columns of a row are separated
+type columnFormat int
+
+const (
+	undetected columnFormat = iota
+	commaSeparated
+	tabSeparated
+	whitespaceSeparated
+)
+
+func (f columnFormat) String() string {
+	switch f {
+	case commaSeparated:
+		return "CSV"
+	case tabSeparated:
+		return "TSV"
+	case whitespaceSeparated:
+		return "whitespace separated"
 	}
-	defer f.Close()
+	return "undetected"
+}
 
-	csvReader := csv.NewReader(f)
-	records, err := csvReader.ReadAll()
-	if err != nil {
-		log.Fatal("Unable to parse file as CSV for "+filePath, err)
+// detectFormat guesses the column format from a row: a comma means CSV, otherwise a tab means TSV,
+// otherwise the columns are separated by spaces like the official puzzle input
+func detectFormat(line string) columnFormat {
+	if strings.Contains(line, ",") {
+		return commaSeparated
+	}
+	if strings.Contains(line, "\t") {
+		return tabSeparated
 	}
+	return whitespaceSeparated
+}
 
-	return records
+// listReader reads the left and right location IDs one row at a time, so large lists never have to be
+// held as text. The format is detected from the first row and every other row must match it.
+type listReader struct {
+	scanner *bufio.Scanner
+	format  columnFormat
+	line    int
 }
 
-// ParseInt converts a string to an integer
-func parseInt(str string) int {
-	num, err := strconv.Atoi(str)
+func newListReader(file *os.File) *listReader {
+	return &listReader{scanner: bufio.NewScanner(file)}
+}
+
+// next returns the next row, skipping blank lines. ok is false at the end of the input.
+func (r *listReader) next() (left, right int, ok bool, err error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := helper.Line{Number: r.line, Text: strings.TrimSpace(r.scanner.Text())}
+		if line.Text == "" {
+			continue
+		}
+		if r.format == undetected {
+			r.format = detectFormat(line.Text)
+		}
+
+		var columns []string
+		switch r.format {
+		case commaSeparated:
+			if columns, err = parseCSVRow(line.Text); err != nil {
+				return 0, 0, false, helper.NewLineError(line, "%v", err)
+			}
+		case tabSeparated:
+			columns = strings.Split(line.Text, "\t")
+		default:
+			columns = strings.Fields(line.Text)
+		}
+		if len(columns) != 2 {
+			return 0, 0, false, helper.NewLineError(line, "expected 2 %s columns, got %d", r.format, len(columns))
+		}
+		if left, err = strconv.Atoi(strings.TrimSpace(columns[0])); err != nil {
+			return 0, 0, false, helper.NewLineError(line, "left location ID is not a number")
+		}
+		if right, err = strconv.Atoi(strings.TrimSpace(columns[1])); err != nil {
+			return 0, 0, false, helper.NewLineError(line, "right location ID is not a number")
+		}
+		return left, right, true, nil
+	}
+	return 0, 0, false, r.scanner.Err()
+}
+
+// parseCSVRow splits a row with encoding/csv, so fields may be quoted. Rows are read one line at a time,
+// so a quoted field can't span lines.
+func parseCSVRow(text string) ([]string, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.TrimLeadingSpace = true
+	columns, err := reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, fmt.Errorf("invalid CSV at column %d: %v", parseErr.Column, parseErr.Err)
+	}
+	return columns, err
+}
+
+// readLists calls row for every row of the input file
+func readLists(filePath string, row func(left, right int)) error {
+	file, err := os.Open(filePath) // #nosec G304
 	if err != nil {
-		log.Fatal("Error converting string to int:", err)
+		return err
+	}
+	defer file.Close()
+
+	reader := newListReader(file)
+	for {
+		left, right, ok, err := reader.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		row(left, right)
 	}
-	return num
 }
 
 // Abs returns the absolute value of an integer
@@ -43,32 +142,20 @@ func abs(num int) int {
 	return num
 }
 
-// getOccurences returns the number of times a number appears in a sorted list
-func getOccurences(searchNum int, list []int) int {
-	occurences := 0
-	for _, num := range list {
-		if num == searchNum {
-			occurences++
-		}
-		if num > searchNum {
-			break
-		}
-	}
-	return occurences
-}
-
-func getSortedLists() ([]int, []int) {
-	inputData := readCsvFile("input.csv")
+func getSortedLists(filePath string) ([]int, []int, error) {
 	var leftList, rightList []int
-	for _, row := range inputData {
-		leftList = append(leftList, parseInt(row[0]))
-		rightList = append(rightList, parseInt(row[1]))
+	err := readLists(filePath, func(left, right int) {
+		leftList = append(leftList, left)
+		rightList = append(rightList, right)
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
 	sort.Ints(leftList)
 	sort.Ints(rightList)
 
-	return leftList, rightList
+	return leftList, rightList, nil
 }
 
 func getTotalDistance(leftList, rightList []int) int {
@@ -79,18 +166,121 @@ func getTotalDistance(leftList, rightList []int) int {
 	return totalDistance
 }
 
+// getFrequencies counts how many times each number appears in a list
+func getFrequencies(list []int) map[int]int {
+	frequencies := make(map[int]int)
+	for _, num := range list {
+		frequencies[num]++
+	}
+	return frequencies
+}
+
 func getSimilarityScore(leftList, rightList []int) int {
+	rightFrequencies := getFrequencies(rightList)
 	totalSimilarity := 0
 	for _, leftNum := range leftList {
-		occurences := getOccurences(leftNum, rightList)
-		totalSimilarity += (leftNum * occurences)
+		totalSimilarity += leftNum * rightFrequencies[leftNum]
 	}
 
 	return totalSimilarity
 }
 
+// getCounts reads the input keeping only how often each location ID appears in each list, so memory
+// grows with the number of distinct IDs rather than the number of rows
+func getCounts(filePath string) (map[int]int, map[int]int, error) {
+	leftCounts := make(map[int]int)
+	rightCounts := make(map[int]int)
+	err := readLists(filePath, func(left, right int) {
+		leftCounts[left]++
+		rightCounts[right]++
+	})
+	return leftCounts, rightCounts, err
+}
+
+// sortedCounts returns the distinct numbers in ascending order along with how often each appears
+func sortedCounts(counts map[int]int) ([]int, []int) {
+	values := make([]int, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Ints(values)
+	repeats := make([]int, len(values))
+	for i, value := range values {
+		repeats[i] = counts[value]
+	}
+	return values, repeats
+}
+
+// getTotalDistanceFromCounts pairs the lists smallest to largest like getTotalDistance, walking runs of
+// equal values instead of individual rows
+func getTotalDistanceFromCounts(leftCounts, rightCounts map[int]int) int {
+	leftValues, leftRepeats := sortedCounts(leftCounts)
+	rightValues, rightRepeats := sortedCounts(rightCounts)
+
+	totalDistance := 0
+	l, r := 0, 0
+	for l < len(leftValues) && r < len(rightValues) {
+		pairs := helper.Min(leftRepeats[l], rightRepeats[r])
+		totalDistance += pairs * abs(leftValues[l]-rightValues[r])
+		leftRepeats[l] -= pairs
+		rightRepeats[r] -= pairs
+		if leftRepeats[l] == 0 {
+			l++
+		}
+		if rightRepeats[r] == 0 {
+			r++
+		}
+	}
+	return totalDistance
+}
+
+func getSimilarityScoreFromCounts(leftCounts, rightCounts map[int]int) int {
+	totalSimilarity := 0
+	for value, count := range leftCounts {
+		totalSimilarity += value * count * rightCounts[value]
+	}
+	return totalSimilarity
+}
+
+var stream = flag.Bool("stream", false, "read the lists row by row keeping only a count of each location ID, so memory grows with the number of distinct IDs rather than rows")
+
+func init() {
+	runner.Register(1, 1, part1)
+	runner.Register(1, 2, part2)
+}
+
+// part1 returns the total distance between the lists
+func part1(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	if *stream {
+		leftCounts, rightCounts, err := getCounts(inputPath)
+		if err != nil {
+			return nil, err
+		}
+		return getTotalDistanceFromCounts(leftCounts, rightCounts), nil
+	}
+	leftList, rightList, err := getSortedLists(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return getTotalDistance(leftList, rightList), nil
+}
+
+// part2 returns the similarity score of the lists
+func part2(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	if *stream {
+		leftCounts, rightCounts, err := getCounts(inputPath)
+		if err != nil {
+			return nil, err
+		}
+		return getSimilarityScoreFromCounts(leftCounts, rightCounts), nil
+	}
+	leftList, rightList, err := getSortedLists(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return getSimilarityScore(leftList, rightList), nil
+}
+
 func main() {
-	leftList, rightList := getSortedLists()
-	fmt.Println("Day 1, Part 1: Total Distance =", getTotalDistance(leftList, rightList))
-	fmt.Println("Day 1, Part 2: Similarity Score =", getSimilarityScore(leftList, rightList))
+	runner.Main()
 }
@@ -1,153 +1,118 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
+	"context"
+	"encoding/json"
+	"flag"
 	"log"
+	"log/slog"
 	"os"
-	"strconv"
-	"strings"
+
+	"advent-of-code-2024/day02/reports"
+	"advent-of-code-2024/runner"
 )
 
-func getInputData() [][]string {
-	file, err := os.Open("input.txt")
+func getInputData(inputPath string, policy reports.DegeneratePolicy) ([][]int, error) {
+	file, err := os.Open(inputPath) // #nosec G304
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer file.Close()
-
-	var data [][]string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		data = append(data, strings.Fields(line))
-	}
-
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
-
-	return data
+	return reports.Parse(file, policy)
 }
 
-func getIntList(list []string) []int {
-	intList := make([]int, len(list))
-	for i, num := range list {
-		intList[i], _ = strconv.Atoi(num)
+func day02_1(inputData [][]int, validator reports.Validator) int {
+	safeReports := 0
+	for _, levels := range inputData {
+		// Check both rules and if both pass increment safe reports counter
+		if validator.Check(levels).Safe {
+			safeReports++
+		}
 	}
-	return intList
+	return safeReports
 }
 
-func checkRule1(list []int) bool {
-	increasing := false
-	decreasing := false
-	for i := 0; i < len(list)-1; i++ {
-		// If these are the first values, check if the list is increasing or decreasing
-		if !increasing && !decreasing {
-			// If the first two values are equal, the list is invalid
-			if list[i] == list[i+1] {
-				return false
-			}
-			// If the first value is less than the second value, the list is increasing
-			if list[i] < list[i+1] {
-				increasing = true
-			}
-			// If the first value is greater than the second value, the list is decreasing
-			if list[i] > list[i+1] {
-				decreasing = true
-			}
-		}
-		// If the previous values were increasing make sure the next value continues to be increasing
-		if increasing {
-			if list[i] >= list[i+1] {
-				return false
-			}
-		}
-		// If the previous values were decreasing make sure the next value continues to be decreasing
-		if decreasing {
-			if list[i] <= list[i+1] {
-				return false
-			}
+func day02_2(inputData [][]int, validator reports.Validator) int {
+	safeReports := 0
+	for _, levels := range inputData {
+		if validator.Check(levels).DampenedSafe {
+			safeReports++
 		}
 	}
-	// If the loop completes without returning, the list is valid
-	return true
+	return safeReports
 }
 
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
+// Report is the verdict for one line of the input
+type Report struct {
+	Line int `json:"line"`
+	reports.Verdict
 }
 
-func checkRule2(list []int) bool {
-	for i := 0; i < len(list)-1; i++ {
-		difference := abs(list[i+1] - list[i])
-		// If the difference is greater than 3 or less than 1, the list is invalid so return the index of the error
-		if difference > 3 || difference < 1 {
-			return false
-		}
+// printReports writes the dampened verdict of every report as JSON
+func printReports(inputData [][]int, validator reports.Validator) error {
+	all := make([]Report, len(inputData))
+	for i, levels := range inputData {
+		all[i] = Report{Line: i + 1, Verdict: validator.Check(levels)}
 	}
-	// If the loop completes without returning, the list is valid so the error index is -1
-	return true
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(all)
 }
 
-func checkRules(list []int) bool {
-	if !checkRule1(list) {
-		return false
-	}
-	if !checkRule2(list) {
-		return false
-	}
-	return true
-}
+var (
+	jsonReport = flag.Bool("json", false, "print the verdict for every report as JSON instead of the answers")
+	tolerance  = flag.Int("tolerance", 1, "most levels the Problem Dampener may remove in part 2")
+	minStep    = flag.Int("min-step", 1, "smallest allowed difference between adjacent levels")
+	maxStep    = flag.Int("max-step", 3, "largest allowed difference between adjacent levels")
+	degenerate = flag.String("degenerate", "safe", "how to treat reports with fewer than two levels (safe, unsafe or reject)")
+)
 
-func day02_1(inputData [][]string) int {
-	safeReports := 0
-	for _, row := range inputData {
-		// Convert row to int list
-		intList := getIntList(row)
+func init() {
+	runner.Register(2, 1, part1)
+	runner.Register(2, 2, part2)
+}
 
-		// Check both rules and if both pass increment safe reports counter
-		if checkRules(intList) {
-			safeReports++
-		}
+// setup reads the reports and builds the validator described by the flags
+func setup(inputPath string) ([][]int, reports.Validator, error) {
+	policy, err := reports.ParseDegeneratePolicy(*degenerate)
+	if err != nil {
+		return nil, reports.Validator{}, err
 	}
-	return safeReports
+	validator := reports.Validator{MinStep: *minStep, MaxStep: *maxStep, Tolerance: *tolerance, Degenerate: policy}
+	if err := validator.Validate(); err != nil {
+		return nil, reports.Validator{}, err
+	}
+	inputData, err := getInputData(inputPath, policy)
+	return inputData, validator, err
 }
 
-func removeIndex(list []int, index int) []int {
-	newList := []int{}
-	for i := 0; i < len(list); i++ {
-		if i != index {
-			newList = append(newList, list[i])
-		}
+func part1(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	inputData, validator, err := setup(inputPath)
+	if err != nil {
+		return nil, err
 	}
-	return newList
+	return day02_1(inputData, validator), nil
 }
 
-func day02_2(inputData [][]string) int {
-	safeReports := 0
-	for _, row := range inputData {
-		intList := getIntList(row)
-		if checkRules(intList) {
-			safeReports++
-		} else { // If the row is invalid, check each possible row with one value removed
-			for i := 0; i < len(row); i++ {
-				newList := removeIndex(intList, i)
-				if checkRules(newList) {
-					safeReports++
-					break
-				}
-			}
-		}
+func part2(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	inputData, validator, err := setup(inputPath)
+	if err != nil {
+		return nil, err
 	}
-	return safeReports
+	return day02_2(inputData, validator), nil
 }
 
 func main() {
-	inputData := getInputData()
-	fmt.Println("Day 2, Part 1: Total safe reports = ", day02_1(inputData))
-	fmt.Println("Day 2, Part 2: Total safe reports after dampening = ", day02_2(inputData))
+	flag.Parse()
+	if *jsonReport {
+		inputData, validator, err := setup(runner.Input(2))
+		if err == nil {
+			err = printReports(inputData, validator)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	runner.Main()
 }
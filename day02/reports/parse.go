@@ -0,0 +1,64 @@
+package reports
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"advent-of-code-2024/helper"
+)
+
+// DegeneratePolicy decides what happens to reports with fewer than two levels, which have no steps to check
+type DegeneratePolicy int
+
+const (
+	DegenerateSafe   DegeneratePolicy = iota // Count them as safe, as the original solution did
+	DegenerateUnsafe                         // Count them as unsafe, including reports the dampener shrinks below two levels
+	DegenerateReject                         // Refuse them when parsing
+)
+
+// ParseDegeneratePolicy converts a policy name (safe, unsafe or reject) to a DegeneratePolicy
+func ParseDegeneratePolicy(name string) (DegeneratePolicy, error) {
+	switch name {
+	case "safe":
+		return DegenerateSafe, nil
+	case "unsafe":
+		return DegenerateUnsafe, nil
+	case "reject":
+		return DegenerateReject, nil
+	}
+	return DegenerateSafe, fmt.Errorf("unknown degenerate report policy '%s'", name)
+}
+
+// Parse reads one report per line, with levels separated by any whitespace.
+// Every token must be an integer, and negative levels are allowed. Each line is a report, including
+// blank ones, so report i comes from line i+1.
+func Parse(r io.Reader, policy DegeneratePolicy) ([][]int, error) {
+	var reports [][]int
+	scanner := bufio.NewScanner(r)
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := helper.Line{Number: number, Text: scanner.Text()}
+
+		fields := strings.Fields(line.Text)
+		levels := make([]int, len(fields))
+		for i, field := range fields {
+			level, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, helper.NewLineError(line, "level %d '%s' is not an integer", i+1, field)
+			}
+			levels[i] = level
+		}
+		if len(levels) < 2 && policy == DegenerateReject {
+			return nil, helper.NewLineError(line, "report has %d levels, at least 2 are needed", len(levels))
+		}
+		reports = append(reports, levels)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
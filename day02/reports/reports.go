@@ -0,0 +1,209 @@
+package reports
+
+import (
+	"fmt"
+
+	"advent-of-code-2024/helper"
+)
+
+// Rule names a rule a report has to follow to be safe
+type Rule string
+
+const (
+	Monotonic Rule = "monotonic"      // Levels must be all increasing or all decreasing
+	StepSize  Rule = "step size"      // Adjacent levels must differ by at least MinStep and at most MaxStep
+	TooFew    Rule = "too few levels" // Reports need at least two levels unless degenerate reports count as safe
+)
+
+// Verdict explains whether a report is safe, and if not, why
+type Verdict struct {
+	Levels []int `json:"levels"`
+	Safe   bool  `json:"safe"`
+	// The first rule broken and the index of the level that broke it, when unsafe
+	FailedRule     Rule `json:"failedRule,omitempty"`
+	OffendingIndex int  `json:"offendingIndex"`
+	// Whether the report is safe with the Problem Dampener, and the indices of the levels it removed
+	DampenedSafe bool  `json:"dampenedSafe"`
+	Removed      []int `json:"removed"`
+}
+
+// Validator checks reports against configurable rules
+type Validator struct {
+	MinStep   int // Smallest allowed difference between adjacent levels
+	MaxStep   int // Largest allowed difference between adjacent levels
+	Tolerance int // Most levels the Problem Dampener may remove
+	// Whether reports with fewer than two levels are safe. DegenerateReject is handled by Parse, and
+	// is treated like DegenerateUnsafe here.
+	Degenerate DegeneratePolicy
+}
+
+// NewValidator returns a validator with the puzzle's rules: steps of 1 to 3, the given tolerance, and
+// degenerate reports counted as safe
+func NewValidator(tolerance int) Validator {
+	return Validator{MinStep: 1, MaxStep: 3, Tolerance: tolerance, Degenerate: DegenerateSafe}
+}
+
+// Validate checks the rules can be applied: steps of at least 1, a step range that isn't empty, and
+// a tolerance that isn't negative
+func (v Validator) Validate() error {
+	if v.MinStep < 1 {
+		return fmt.Errorf("steps must be at least 1, got a minimum of %d", v.MinStep)
+	}
+	if v.MinStep > v.MaxStep {
+		return fmt.Errorf("steps can't be at least %d and at most %d", v.MinStep, v.MaxStep)
+	}
+	if v.Tolerance < 0 {
+		return fmt.Errorf("the Problem Dampener can't remove %d levels", v.Tolerance)
+	}
+	return nil
+}
+
+// minKept is the fewest levels a report must have left to be safe
+func (v Validator) minKept() int {
+	if v.Degenerate == DegenerateSafe {
+		return 0
+	}
+	return 2
+}
+
+// Check validates a report, and if it is unsafe finds levels to remove that make it safe within the tolerance
+func (v Validator) Check(levels []int) Verdict {
+	verdict := Verdict{Levels: levels, Removed: []int{}}
+	verdict.FailedRule, verdict.OffendingIndex = v.firstBrokenRule(levels)
+	verdict.Safe = verdict.FailedRule == ""
+	if verdict.Safe {
+		verdict.DampenedSafe = true
+		return verdict
+	}
+
+	for _, direction := range []int{1, -1} {
+		if removed, ok := v.dampen(levels, direction); ok {
+			verdict.DampenedSafe = true
+			verdict.Removed = removed
+			break
+		}
+	}
+	return verdict
+}
+
+// firstBrokenRule walks the report's adjacent pairs and returns the first rule broken and the index
+// of the second level of the pair that broke it, or "" and -1 if the report is safe.
+// The direction is set by the first pair, and a pair that doesn't change breaks Monotonic.
+func (v Validator) firstBrokenRule(levels []int) (Rule, int) {
+	if len(levels) < v.minKept() {
+		return TooFew, -1
+	}
+	direction := 0
+	for i := 1; i < len(levels); i++ {
+		difference := levels[i] - levels[i-1]
+		if difference == 0 {
+			return Monotonic, i
+		}
+		if direction == 0 {
+			direction = sign(difference)
+		}
+		if sign(difference) != direction {
+			return Monotonic, i
+		}
+		if step := abs(difference); step < v.MinStep || step > v.MaxStep {
+			return StepSize, i
+		}
+	}
+	return "", -1
+}
+
+// validStep checks that going from level a to level b moves in the direction by an allowed step
+func (v Validator) validStep(a, b, direction int) bool {
+	step := (b - a) * direction
+	return step >= v.MinStep && step <= v.MaxStep && step > 0
+}
+
+// dampen decides whether removing at most Tolerance levels leaves a report that moves in the direction
+// with allowed steps, and if so returns the removed indices.
+//
+// reachable[i] is a bitset whose bit j is set when level i can be kept with exactly j levels removed
+// before it. The level kept before i is at most Tolerance+1 places back, and reaching i from p removes
+// the i-p-1 levels between them, so each transition is a shift and OR of a whole bitset. That makes
+// the check O(n·k) word operations for tolerance k (one word per bitset up to k = 63).
+func (v Validator) dampen(levels []int, direction int) ([]int, bool) {
+	n, k := len(levels), v.Tolerance
+	if n <= k && v.minKept() == 0 {
+		// Everything can be removed
+		removed := make([]int, n)
+		for i := range removed {
+			removed[i] = i
+		}
+		return removed, true
+	}
+
+	reachable := make([]helper.Bitset, n)
+	for i := range reachable {
+		reachable[i] = helper.NewBitset(k + 1)
+		if i <= k {
+			// Remove every level before i
+			reachable[i].Set(i)
+		}
+		for p := i - 1; p >= 0 && p >= i-1-k; p-- {
+			if v.validStep(levels[p], levels[i], direction) {
+				reachable[i].UnionShifted(reachable[p], i-1-p)
+			}
+		}
+	}
+
+	// Find a last kept level with few enough removals before it to also remove everything after it,
+	// keeping enough levels for the degenerate report policy
+	for last := n - 1; last >= 0 && last >= n-1-k; last-- {
+		for j := 0; j+(n-1-last) <= k && last-j+1 >= v.minKept(); j++ {
+			if reachable[last].Has(j) {
+				return v.backtrack(levels, direction, reachable, last, j), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// backtrack recovers the removed indices for a last kept level reached with the given removals before it
+func (v Validator) backtrack(levels []int, direction int, reachable []helper.Bitset, last, removals int) []int {
+	var removed []int
+	for r := len(levels) - 1; r > last; r-- {
+		removed = append(removed, r)
+	}
+
+	// While some level before i is kept, find one that accounts for the removals
+	i, j := last, removals
+	for j != i {
+		for p := i - 1; p >= i-1-j; p-- {
+			gap := i - 1 - p
+			if v.validStep(levels[p], levels[i], direction) && reachable[p].Has(j-gap) {
+				for r := i - 1; r > p; r-- {
+					removed = append(removed, r)
+				}
+				i, j = p, j-gap
+				break
+			}
+		}
+	}
+	for r := i - 1; r >= 0; r-- {
+		removed = append(removed, r)
+	}
+
+	// Indices were collected from the end
+	for a, b := 0, len(removed)-1; a < b; a, b = a+1, b-1 {
+		removed[a], removed[b] = removed[b], removed[a]
+	}
+	return removed
+}
+
+func sign(x int) int {
+	if x < 0 {
+		return -1
+	}
+	return 1
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,172 @@
+package reports
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const example = `7 6 4 2 1
+1 2 7 8 9
+9 7 6 2 1
+1 3 2 4 5
+8 6 4 4 1
+1 3 6 7 9`
+
+func TestCheckExample(t *testing.T) {
+	levels, err := Parse(strings.NewReader(example), DegenerateSafe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tolerance    int
+		wantSafe     []bool
+		wantDampened []bool
+		wantRemoved  [][]int
+	}{
+		{0, []bool{true, false, false, false, false, true}, []bool{true, false, false, false, false, true},
+			[][]int{{}, {}, {}, {}, {}, {}}},
+		{1, []bool{true, false, false, false, false, true}, []bool{true, false, false, true, true, true},
+			[][]int{{}, {}, {}, {1}, {2}, {}}},
+	}
+
+	for _, tt := range tests {
+		validator := NewValidator(tt.tolerance)
+		for i, report := range levels {
+			got := validator.Check(report)
+			if got.Safe != tt.wantSafe[i] || got.DampenedSafe != tt.wantDampened[i] {
+				t.Errorf("tolerance %d, report %v: got safe %t dampened %t, want %t and %t",
+					tt.tolerance, report, got.Safe, got.DampenedSafe, tt.wantSafe[i], tt.wantDampened[i])
+			}
+			if got.DampenedSafe && !reflect.DeepEqual(got.Removed, tt.wantRemoved[i]) {
+				t.Errorf("tolerance %d, report %v: got removed %v, want %v", tt.tolerance, report, got.Removed, tt.wantRemoved[i])
+			}
+		}
+	}
+}
+
+// bruteForce tries removing every subset of at most Tolerance levels and reports whether any leaves a safe report
+func bruteForce(v Validator, levels []int) bool {
+	n := len(levels)
+	for mask := 0; mask < 1<<n; mask++ {
+		var kept []int
+		removed := 0
+		for i, level := range levels {
+			if mask&(1<<i) != 0 {
+				removed++
+			} else {
+				kept = append(kept, level)
+			}
+		}
+		if removed <= v.Tolerance {
+			if rule, _ := v.firstBrokenRule(kept); rule == "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TestCheckMatchesBruteForce compares the dampener with trying every removal on small random reports, and
+// checks the levels it removes really do leave a safe report
+func TestCheckMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	for _, policy := range []DegeneratePolicy{DegenerateSafe, DegenerateUnsafe} {
+		for tolerance := 0; tolerance <= 4; tolerance++ {
+			v := NewValidator(tolerance)
+			v.Degenerate = policy
+			for trial := 0; trial < 2000; trial++ {
+				levels := make([]int, random.Intn(9))
+				for i := range levels {
+					levels[i] = random.Intn(10)
+				}
+
+				got := v.Check(levels)
+				if want := bruteForce(v, levels); got.DampenedSafe != want {
+					t.Fatalf("policy %d, tolerance %d, report %v: got dampened safe %t, want %t",
+						policy, tolerance, levels, got.DampenedSafe, want)
+				}
+				if !got.DampenedSafe {
+					continue
+				}
+				if len(got.Removed) > tolerance {
+					t.Fatalf("policy %d, tolerance %d, report %v: removed %v, more than the tolerance",
+						policy, tolerance, levels, got.Removed)
+				}
+				removed := make(map[int]bool)
+				for j, index := range got.Removed {
+					if j > 0 && index <= got.Removed[j-1] {
+						t.Fatalf("policy %d, tolerance %d, report %v: removed %v, not in increasing order",
+							policy, tolerance, levels, got.Removed)
+					}
+					removed[index] = true
+				}
+				var kept []int
+				for i, level := range levels {
+					if !removed[i] {
+						kept = append(kept, level)
+					}
+				}
+				if rule, index := v.firstBrokenRule(kept); rule != "" {
+					t.Fatalf("policy %d, tolerance %d, report %v: removing %v leaves %v, which breaks %s at %d",
+						policy, tolerance, levels, got.Removed, kept, rule, index)
+				}
+			}
+		}
+	}
+}
+
+func TestDegeneratePolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       DegeneratePolicy
+		levels       []int
+		wantSafe     bool
+		wantDampened bool
+	}{
+		{"one level counted as safe", DegenerateSafe, []int{5}, true, true},
+		{"one level counted as unsafe", DegenerateUnsafe, []int{5}, false, false},
+		{"dampened to one level counted as safe", DegenerateSafe, []int{5, 5}, false, true},
+		{"dampened to one level counted as unsafe", DegenerateUnsafe, []int{5, 5}, false, false},
+		{"two levels left counted as unsafe", DegenerateUnsafe, []int{1, 9, 2}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator(1)
+			v.Degenerate = tt.policy
+			got := v.Check(tt.levels)
+			if got.Safe != tt.wantSafe || got.DampenedSafe != tt.wantDampened {
+				t.Errorf("got safe %t dampened %t, want %t and %t", got.Safe, got.DampenedSafe, tt.wantSafe, tt.wantDampened)
+			}
+		})
+	}
+
+	if _, err := Parse(strings.NewReader("1 2 3\n\n4 5 6\n"), DegenerateReject); err == nil {
+		t.Error("parsed a blank report with degenerate reports rejected")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       Validator
+		wantErr bool
+	}{
+		{"puzzle rules", NewValidator(1), false},
+		{"no dampener", NewValidator(0), false},
+		{"negative tolerance", NewValidator(-1), true},
+		{"zero step", Validator{MinStep: 0, MaxStep: 3}, true},
+		{"empty step range", Validator{MinStep: 4, MaxStep: 3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
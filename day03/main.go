@@ -1,80 +1,113 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"flag"
+	"io"
+	"log/slog"
 	"os"
-	"strconv"
-	"strings"
+
+	"advent-of-code-2024/day03/memory"
+	"advent-of-code-2024/runner"
 )
 
-func getInputData() string {
-	content, err := os.ReadFile("input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	return string(content)
-}
+var (
+	minDigits = flag.Int("min-digits", 1, "fewest digits allowed in an operand")
+	maxDigits = flag.Int("max-digits", 3, "most digits allowed in an operand")
+	extended  = flag.Bool("extended", false, "also recognise add(a,b), sub(a,b) and toggle()")
+	stream    = flag.Bool("stream", false, "process the input incrementally with bounded memory instead of reading it all first")
+)
+
+// stdinResult keeps the result of running standard input, which can only be read once for both parts
+var stdinResult *memory.Result
 
-func isNumber(char string) bool {
-	return char >= "0" && char <= "9"
+func init() {
+	runner.Register(3, 1, part1)
+	runner.Register(3, 2, part2)
 }
 
-func calculateMuls(data string) int {
-	total := 0
-	for i := 0; i < len(data)-4; i++ { // Need at least "mul()" (4 chars)
-		if data[i:i+4] == "mul(" {
-			// Found start of potential mul expression
-			j := i + 4 // Position after "mul("
+func getInputData(path string) (string, error) {
+	if path == "-" {
+		content, err := io.ReadAll(os.Stdin)
+		return string(content), err
+	}
+	content, err := os.ReadFile(path) // #nosec G304
+	return string(content), err
+}
 
-			// Get first number
-			num1Str := ""
-			for j < len(data) && isNumber(string(data[j])) {
-				num1Str += string(data[j])
-				j++
+// newGrammar builds the grammar described by the flags
+func newGrammar() (*memory.Grammar, error) {
+	grammar := memory.NewGrammar()
+	grammar.MinDigits = *minDigits
+	grammar.MaxDigits = *maxDigits
+	if err := grammar.Validate(); err != nil {
+		return nil, err
+	}
+	if *extended {
+		for _, instruction := range []memory.Instruction{memory.Add, memory.Sub, memory.Toggle} {
+			if err := grammar.Register(instruction); err != nil {
+				return nil, err
 			}
+		}
+	}
+	return grammar, nil
+}
 
-			// Check for comma
-			if j < len(data) && data[j] == ',' {
-				j++ // Move past comma
-
-				// Get second number
-				num2Str := ""
-				for j < len(data) && isNumber(string(data[j])) {
-					num2Str += string(data[j])
-					j++
-				}
+// run executes the memory dump at path, or standard input for "-", logging every instruction that fails to parse
+func run(path string, logger *slog.Logger) (memory.Result, error) {
+	if path == "-" && stdinResult != nil {
+		return *stdinResult, nil
+	}
+	grammar, err := newGrammar()
+	if err != nil {
+		return memory.Result{}, err
+	}
+	onParseError := func(parseErr memory.ParseError) {
+		logger.Debug("parse error", "offset", parseErr.Offset, "reason", parseErr.Reason, "text", parseErr.Text)
+	}
 
-				// Check for closing parenthesis
-				if j < len(data) && data[j] == ')' {
-					// Valid mul expression found
-					num1, _ := strconv.Atoi(num1Str)
-					num2, _ := strconv.Atoi(num2Str)
-					total += num1 * num2
-				}
+	var result memory.Result
+	if *stream {
+		reader := os.Stdin
+		if path != "-" {
+			file, err := os.Open(path) // #nosec G304
+			if err != nil {
+				return result, err
 			}
+			defer file.Close()
+			reader = file
+		}
+		if result, err = memory.RunReader(grammar, reader, onParseError); err != nil {
+			return result, err
+		}
+	} else {
+		data, err := getInputData(path)
+		if err != nil {
+			return result, err
 		}
+		tokens, parseErrors := grammar.Lex(data)
+		for _, parseErr := range parseErrors {
+			onParseError(parseErr)
+		}
+		result = memory.Run(grammar, tokens)
 	}
-	return total
-}
 
-func calcEnabledMuls(data string) int {
-	total := 0
+	if path == "-" {
+		stdinResult = &result
+	}
+	return result, nil
+}
 
-	// Split the data on "do()" to get all the possible expressions. Enabled by default so the expression at index 0 is included
-	splits := strings.Split(data, "do()")
-	for _, split := range splits {
-		// Get the part of the expression before the first don't()
-		enabledExpression := strings.Split(split, "don't()")[0]
+func part1(_ context.Context, inputPath string, logger *slog.Logger) (any, error) {
+	result, err := run(inputPath, logger)
+	return result.Total, err
+}
 
-		// Calculate the muls in the enabled expression and add to total
-		total += calculateMuls(enabledExpression)
-	}
-	return total
+func part2(_ context.Context, inputPath string, logger *slog.Logger) (any, error) {
+	result, err := run(inputPath, logger)
+	return result.Enabled, err
 }
 
 func main() {
-	data := getInputData()
-	fmt.Println("Day 3, Part 1:", calculateMuls(data))
-	fmt.Println("Day 3, Part 2:", calcEnabledMuls(data))
+	runner.Main()
 }
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Instruction describes an instruction written as name(a,b,...) with Arity integer operands
+type Instruction struct {
+	Name  string
+	Arity int
+	Exec  func(in *Interpreter, args []int)
+}
+
+// The puzzle's instructions
+var (
+	Mul = Instruction{Name: "mul", Arity: 2, Exec: func(in *Interpreter, args []int) {
+		in.Accumulate(args[0] * args[1])
+	}}
+	Do = Instruction{Name: "do", Arity: 0, Exec: func(in *Interpreter, args []int) {
+		in.SetEnabled(true)
+	}}
+	Dont = Instruction{Name: "don't", Arity: 0, Exec: func(in *Interpreter, args []int) {
+		in.SetEnabled(false)
+	}}
+)
+
+// Extra instructions that can be registered on top of the puzzle's
+var (
+	Add = Instruction{Name: "add", Arity: 2, Exec: func(in *Interpreter, args []int) {
+		in.Accumulate(args[0] + args[1])
+	}}
+	Sub = Instruction{Name: "sub", Arity: 2, Exec: func(in *Interpreter, args []int) {
+		in.Accumulate(args[0] - args[1])
+	}}
+	Toggle = Instruction{Name: "toggle", Arity: 0, Exec: func(in *Interpreter, args []int) {
+		in.SetEnabled(!in.Enabled())
+	}}
+)
+
+// Grammar is the set of instructions the lexer recognises and the rules for their operands
+type Grammar struct {
+	MinDigits    int // Fewest digits allowed in an operand
+	MaxDigits    int // Most digits allowed in an operand
+	instructions []Instruction
+}
+
+// NewGrammar returns the puzzle's grammar: mul, do and don't with operands of 1 to 3 digits
+func NewGrammar() *Grammar {
+	g := &Grammar{MinDigits: 1, MaxDigits: 3}
+	for _, instruction := range []Instruction{Mul, Do, Dont} {
+		if err := g.Register(instruction); err != nil {
+			panic(err)
+		}
+	}
+	return g
+}
+
+// Validate checks the operand limits: every operand needs at least one digit, and no more than MaxDigits
+func (g *Grammar) Validate() error {
+	if g.MinDigits < 1 {
+		return fmt.Errorf("operands need at least 1 digit, got a minimum of %d", g.MinDigits)
+	}
+	if g.MinDigits > g.MaxDigits {
+		return fmt.Errorf("operands can't have at least %d digits and at most %d", g.MinDigits, g.MaxDigits)
+	}
+	return nil
+}
+
+// Register adds an instruction to the grammar
+func (g *Grammar) Register(instruction Instruction) error {
+	if instruction.Name == "" || strings.ContainsAny(instruction.Name, "(),") {
+		return fmt.Errorf("invalid instruction name '%s'", instruction.Name)
+	}
+	if instruction.Arity < 0 || instruction.Exec == nil {
+		return fmt.Errorf("instruction '%s' needs a non-negative arity and an Exec function", instruction.Name)
+	}
+	if _, ok := g.lookup(instruction.Name); ok {
+		return fmt.Errorf("instruction '%s' is already registered", instruction.Name)
+	}
+
+	// Keep longer names first so a name is never matched as the prefix of a longer one
+	g.instructions = append(g.instructions, instruction)
+	sort.SliceStable(g.instructions, func(i, j int) bool {
+		return len(g.instructions[i].Name) > len(g.instructions[j].Name)
+	})
+	return nil
+}
+
+// lookup returns the registered instruction with the given name
+func (g *Grammar) lookup(name string) (Instruction, bool) {
+	for _, instruction := range g.instructions {
+		if instruction.Name == name {
+			return instruction, true
+		}
+	}
+	return Instruction{}, false
+}
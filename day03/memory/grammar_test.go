@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGrammarValidate(t *testing.T) {
+	tests := []struct {
+		name                 string
+		minDigits, maxDigits int
+		wantErr              bool
+	}{
+		{"puzzle limits", 1, 3, false},
+		{"exact width", 3, 3, false},
+		{"no digits", 0, 3, true},
+		{"minimum above maximum", 4, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGrammar()
+			g.MinDigits, g.MaxDigits = tt.minDigits, tt.maxDigits
+			if err := g.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tt.wantErr)
+			}
+			if _, err := RunReader(g, strings.NewReader("mul(123,4)"), nil); (err != nil) != tt.wantErr {
+				t.Errorf("RunReader got error %v, want error: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package memory
+
+// Result holds the totals for both parts of the puzzle after running corrupted memory
+type Result struct {
+	Total   int // Sum of every instruction's value
+	Enabled int // Sum of the values accumulated while enabled
+}
+
+// Interpreter executes tokens one at a time, so memory can be run in a single pass
+type Interpreter struct {
+	grammar *Grammar
+	enabled bool
+	result  Result
+}
+
+// NewInterpreter returns an interpreter for the grammar in its starting state, with accumulation enabled
+func NewInterpreter(grammar *Grammar) *Interpreter {
+	return &Interpreter{grammar: grammar, enabled: true}
+}
+
+// Execute runs a single token. Noise is ignored.
+func (in *Interpreter) Execute(token Token) {
+	if token.IsNoise() {
+		return
+	}
+	if instruction, ok := in.grammar.lookup(token.Name); ok {
+		instruction.Exec(in, token.Args)
+	}
+}
+
+// Accumulate adds an instruction's value to the totals
+func (in *Interpreter) Accumulate(value int) {
+	in.result.Total += value
+	if in.enabled {
+		in.result.Enabled += value
+	}
+}
+
+// Enabled reports whether accumulated values currently count towards Result.Enabled
+func (in *Interpreter) Enabled() bool {
+	return in.enabled
+}
+
+// SetEnabled turns counting towards Result.Enabled on or off
+func (in *Interpreter) SetEnabled(enabled bool) {
+	in.enabled = enabled
+}
+
+// Result returns the totals so far
+func (in *Interpreter) Result() Result {
+	return in.result
+}
+
+// Run executes every token with a new interpreter and returns the totals
+func Run(grammar *Grammar, tokens []Token) Result {
+	in := NewInterpreter(grammar)
+	for _, token := range tokens {
+		in.Execute(token)
+	}
+	return in.Result()
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Token is an instruction or a run of noise found in corrupted memory
+type Token struct {
+	Name   string // Name of the instruction, empty for noise
+	Offset int    // Byte offset of the token's first byte in the input
+	Text   string // The exact bytes of the token
+	Args   []int  // Operands of the instruction, if it has any
+}
+
+// IsNoise reports whether the token is bytes that aren't part of any instruction
+func (t Token) IsNoise() bool {
+	return t.Name == ""
+}
+
+// ParseError describes text that starts like an instruction but doesn't parse as one
+type ParseError struct {
+	Offset int    // Byte offset of the start of the instruction
+	Text   string // The text up to and including the byte where parsing failed
+	Reason string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("offset %d: %s: '%s'", e.Offset, e.Reason, e.Text)
+}
+
+// Lex splits corrupted memory into instruction tokens, with everything between them as noise tokens.
+// It also returns every instruction that failed to parse; their text is included in the noise.
+// The grammar must pass Validate, or no instruction with operands can parse.
+func (g *Grammar) Lex(data string) ([]Token, []ParseError) {
+	var tokens []Token
+	var parseErrors []ParseError
+	noiseStart := 0
+	for i := 0; i < len(data); {
+		token, parseErr, ok := g.lexInstruction(data, i)
+		if parseErr != nil {
+			parseErrors = append(parseErrors, *parseErr)
+		}
+		if !ok {
+			i++
+			continue
+		}
+		if noiseStart < i {
+			tokens = append(tokens, Token{Offset: noiseStart, Text: data[noiseStart:i]})
+		}
+		tokens = append(tokens, token)
+		i += len(token.Text)
+		noiseStart = i
+	}
+	if noiseStart < len(data) {
+		tokens = append(tokens, Token{Offset: noiseStart, Text: data[noiseStart:]})
+	}
+	return tokens, parseErrors
+}
+
+// maxInstructionLength is the most bytes lexInstruction can look at from the start of an instruction
+func (g *Grammar) maxInstructionLength() int {
+	longest := 0
+	for _, instruction := range g.instructions {
+		// name( + operands with one extra digit each + commas + ) + the byte after a failure
+		length := len(instruction.Name) + 1 + instruction.Arity*(g.MaxDigits+1) + instruction.Arity + 2
+		longest = max(longest, length)
+	}
+	return longest
+}
+
+// lexInstruction tries to read an instruction starting at data[offset].
+// It returns a ParseError when the text starts with a registered "name(" but isn't a valid instruction.
+func (g *Grammar) lexInstruction(data string, offset int) (Token, *ParseError, bool) {
+	rest := data[offset:]
+	for _, instruction := range g.instructions {
+		if !strings.HasPrefix(rest, instruction.Name+"(") {
+			continue
+		}
+
+		i := len(instruction.Name) + 1
+		args := make([]int, 0, instruction.Arity)
+		fail := func(at int, format string, a ...any) (Token, *ParseError, bool) {
+			end := at + 1
+			if end > len(rest) {
+				end = len(rest)
+			}
+			return Token{}, &ParseError{Offset: offset, Text: rest[:end], Reason: fmt.Sprintf(format, a...)}, false
+		}
+
+		for n := 0; n < instruction.Arity; n++ {
+			if n > 0 {
+				if i >= len(rest) || rest[i] != ',' {
+					return fail(i, "expected ',' after operand %d of %s", n, instruction.Name)
+				}
+				i++
+			}
+			// Stop one digit past the limit so an instruction never needs more than maxInstructionLength bytes
+			end := i
+			for end < len(rest) && end-i <= g.MaxDigits && rest[end] >= '0' && rest[end] <= '9' {
+				end++
+			}
+			if digits := end - i; digits > g.MaxDigits {
+				return fail(end-1, "operand %d of %s has more than %d digits", n+1, instruction.Name, g.MaxDigits)
+			} else if digits < g.MinDigits {
+				return fail(end, "operand %d of %s has %d digits, expected at least %d", n+1, instruction.Name, digits, g.MinDigits)
+			}
+			arg, err := strconv.Atoi(rest[i:end])
+			if err != nil {
+				return fail(end, "operand %d of %s: %v", n+1, instruction.Name, err)
+			}
+			args = append(args, arg)
+			i = end
+		}
+		if i >= len(rest) || rest[i] != ')' {
+			return fail(i, "expected ')' to close %s after %d operands", instruction.Name, instruction.Arity)
+		}
+		return Token{Name: instruction.Name, Offset: offset, Text: rest[:i+1], Args: args}, nil, true
+	}
+	return Token{}, nil, false
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"errors"
+	"io"
+)
+
+// Scanner lexes corrupted memory incrementally from an io.Reader using a fixed size buffer.
+// It produces the same instruction tokens and parse errors as Grammar.Lex, but long runs of noise
+// may be split into several noise tokens at buffer boundaries.
+type Scanner struct {
+	OnParseError func(ParseError) // Called for each instruction that fails to parse, if set
+
+	grammar   *Grammar
+	reader    io.Reader
+	lookahead int    // Bytes that must be buffered after a position before lexing from it
+	buf       []byte // Holds the unprocessed tail of the previous read followed by new data
+	window    string // The buffered data being lexed
+	base      int    // Stream offset of window[0]
+	eof       bool
+	pending   []Token
+	token     Token
+	err       error
+}
+
+// DefaultBufferSize is the amount of input a Scanner reads at a time
+const DefaultBufferSize = 64 * 1024
+
+// NewScanner returns a Scanner reading from r with the grammar. The grammar must not be changed while scanning.
+func (g *Grammar) NewScanner(r io.Reader) *Scanner {
+	return g.NewScannerSize(r, DefaultBufferSize)
+}
+
+// NewScannerSize is like NewScanner with a chosen buffer size, which is raised if it is too small for the grammar
+func (g *Grammar) NewScannerSize(r io.Reader, size int) *Scanner {
+	lookahead := g.maxInstructionLength()
+	size = max(size, 2*lookahead)
+	return &Scanner{
+		grammar:   g,
+		reader:    r,
+		lookahead: lookahead,
+		buf:       make([]byte, 0, size),
+	}
+}
+
+// Scan advances to the next token, returning false at the end of the input or on a read error
+func (s *Scanner) Scan() bool {
+	for len(s.pending) == 0 {
+		if s.eof || s.err != nil {
+			return false
+		}
+		s.fill()
+		s.lexWindow()
+	}
+	s.token = s.pending[0]
+	s.pending = s.pending[1:]
+	return true
+}
+
+// Token returns the token found by the last call to Scan
+func (s *Scanner) Token() Token {
+	return s.token
+}
+
+// Err returns the first read error, if any
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// fill reads more input after whatever is left of the window
+func (s *Scanner) fill() {
+	s.buf = append(s.buf[:0], s.window...)
+	n, err := s.reader.Read(s.buf[len(s.buf):cap(s.buf)])
+	s.buf = s.buf[:len(s.buf)+n]
+	if errors.Is(err, io.EOF) {
+		s.eof = true
+	} else if err != nil {
+		s.err = err
+	}
+	s.window = string(s.buf)
+}
+
+// lexWindow lexes every position that has enough input after it to decide what is there,
+// or all of the window once the input has ended. The rest is kept for the next fill.
+func (s *Scanner) lexWindow() {
+	safeEnd := len(s.window)
+	if !s.eof {
+		safeEnd -= s.lookahead
+	}
+
+	pos, noiseStart := 0, 0
+	for pos < safeEnd {
+		token, parseErr, ok := s.grammar.lexInstruction(s.window, pos)
+		if parseErr != nil && s.OnParseError != nil {
+			parseErr.Offset += s.base
+			s.OnParseError(*parseErr)
+		}
+		if !ok {
+			pos++
+			continue
+		}
+		if noiseStart < pos {
+			s.pending = append(s.pending, Token{Offset: s.base + noiseStart, Text: s.window[noiseStart:pos]})
+		}
+		token.Offset += s.base
+		s.pending = append(s.pending, token)
+		pos += len(token.Text)
+		noiseStart = pos
+	}
+	if s.eof {
+		pos = max(pos, len(s.window))
+	}
+	if noiseStart < pos {
+		s.pending = append(s.pending, Token{Offset: s.base + noiseStart, Text: s.window[noiseStart:pos]})
+	}
+
+	s.window = s.window[pos:]
+	s.base += pos
+}
+
+// RunReader streams memory from r through a new interpreter and returns the totals
+func RunReader(grammar *Grammar, r io.Reader, onParseError func(ParseError)) (Result, error) {
+	return RunReaderSize(grammar, r, DefaultBufferSize, onParseError)
+}
+
+// RunReaderSize is like RunReader with a chosen buffer size, as for NewScannerSize
+func RunReaderSize(grammar *Grammar, r io.Reader, size int, onParseError func(ParseError)) (Result, error) {
+	if err := grammar.Validate(); err != nil {
+		return Result{}, err
+	}
+	scanner := grammar.NewScannerSize(r, size)
+	scanner.OnParseError = onParseError
+	in := NewInterpreter(grammar)
+	for scanner.Scan() {
+		in.Execute(scanner.Token())
+	}
+	return in.Result(), scanner.Err()
+}
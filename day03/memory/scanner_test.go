@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// extendedGrammar is the puzzle's grammar with add, sub and toggle registered and wider operands
+func extendedGrammar(t *testing.T) *Grammar {
+	t.Helper()
+	g := NewGrammar()
+	g.MinDigits, g.MaxDigits = 2, 4
+	for _, instruction := range []Instruction{Add, Sub, Toggle} {
+		if err := g.Register(instruction); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+// TestScannerMatchesLex streams inputs through the smallest buffer a grammar allows, with padding in front
+// so every instruction starts at every offset from a buffer boundary, and checks the results match lexing
+// the whole input at once
+func TestScannerMatchesLex(t *testing.T) {
+	tests := []struct {
+		name    string
+		grammar func(t *testing.T) *Grammar
+		input   string
+	}{
+		{"puzzle example", func(*testing.T) *Grammar { return NewGrammar() },
+			"xmul(2,4)&mul[3,7]!^don't()_mul(5,5)+mul(32,64](mul(11,8)undo()?mul(8,5))"},
+		{"parse errors", func(*testing.T) *Grammar { return NewGrammar() },
+			"mul(1234,5)mul(1,)don't(x)do(mul(7,mul(1,2)do()mul(,3)don't()mul(999,999)"},
+		{"truncated instruction at the end", func(*testing.T) *Grammar { return NewGrammar() },
+			"mul(4,5)don't()do()mul(1,2"},
+		{"extended instructions", extendedGrammar,
+			"add(12,34)sub(1000,99)toggle()mul(10,20)toggle(add(1,23)sub(12345,10)mul(9999,9999)toggle()don't()add(55,55)"},
+	}
+
+	readers := []struct {
+		name string
+		wrap func(io.Reader) io.Reader
+	}{
+		{"full reads", func(r io.Reader) io.Reader { return r }},
+		{"one byte reads", iotest.OneByteReader},
+		{"half reads", iotest.HalfReader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grammar := tt.grammar(t)
+			size := 2 * grammar.maxInstructionLength()
+			for padding := 0; padding <= size; padding++ {
+				input := strings.Repeat(".", padding) + tt.input
+				tokens, wantErrors := grammar.Lex(input)
+				want := Run(grammar, tokens)
+
+				for _, reader := range readers {
+					var gotErrors []ParseError
+					got, err := RunReaderSize(grammar, reader.wrap(strings.NewReader(input)), 1, func(parseErr ParseError) {
+						gotErrors = append(gotErrors, parseErr)
+					})
+					if err != nil {
+						t.Fatalf("padding %d, %s: %v", padding, reader.name, err)
+					}
+					if got != want {
+						t.Errorf("padding %d, %s: got %+v, want %+v", padding, reader.name, got, want)
+					}
+					if !reflect.DeepEqual(gotErrors, wantErrors) {
+						t.Errorf("padding %d, %s: got parse errors %v, want %v", padding, reader.name, gotErrors, wantErrors)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestScannerInstructionTokens checks the scanner finds the same instructions at the same offsets as Lex
+func TestScannerInstructionTokens(t *testing.T) {
+	grammar := extendedGrammar(t)
+	input := strings.Repeat("add(12,34)xx_sub(1000,99)toggle()mul(10,20)do()", 10)
+
+	tokens, _ := grammar.Lex(input)
+	var want []Token
+	for _, token := range tokens {
+		if !token.IsNoise() {
+			want = append(want, token)
+		}
+	}
+
+	scanner := grammar.NewScannerSize(strings.NewReader(input), 1)
+	var got []Token
+	var noise strings.Builder
+	for scanner.Scan() {
+		if token := scanner.Token(); token.IsNoise() {
+			noise.WriteString(token.Text)
+		} else {
+			got = append(got, token)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got instructions %v, want %v", got, want)
+	}
+	if noise.String() != strings.Repeat("xx_", 10) {
+		t.Errorf("got noise %q, want the bytes between instructions", noise.String())
+	}
+}
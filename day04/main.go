@@ -1,244 +1,76 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
-)
-
-func getInputData() [][]string {
-	file, err := os.Open("input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer file.Close()
 
-	var data [][]string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		var chars []string
-		for _, c := range line {
-			chars = append(chars, string(c))
-		}
-		data = append(data, chars)
-	}
-
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
+	"advent-of-code-2024/day04/wordsearch"
+	"advent-of-code-2024/helper"
+	"advent-of-code-2024/runner"
+)
 
-	return data
+func getInputData(inputPath string) [][]string {
+	return helper.ReadInputToGrid(inputPath)
 }
 
-func checkRight(data [][]string, row, col int) int {
-	if col+3 >= len(data[row]) {
-		return 0
-	}
-	if data[row][col+1] != "M" {
-		return 0
-	}
-	if data[row][col+2] != "A" {
-		return 0
-	}
-	if data[row][col+3] != "S" {
-		return 0
-	}
-	return 1
+func findXMAS(data [][]string) int {
+	return wordsearch.Count(data, []string{"XMAS"}, wordsearch.Options{})
 }
 
-func checkRightDown(data [][]string, row, col int) int {
-	if row+3 >= len(data) || col+3 >= len(data[row]) {
-		return 0
-	}
-	if data[row+1][col+1] != "M" {
-		return 0
-	}
-	if data[row+2][col+2] != "A" {
-		return 0
-	}
-	if data[row+3][col+3] != "S" {
-		return 0
-	}
-	return 1
-}
+// xShapedMAS is two MAS crossing on their A. The other orientations are found as rotations of this one.
+var xShapedMAS = helper.MustParseShape("M.S / .A. / M.S")
 
-func checkDown(data [][]string, row, col int) int {
-	if row+3 >= len(data) {
-		return 0
-	}
-	if data[row+1][col] != "M" {
-		return 0
-	}
-	if data[row+2][col] != "A" {
-		return 0
-	}
-	if data[row+3][col] != "S" {
-		return 0
-	}
-	return 1
+func findXshapedMAS(data [][]string) int {
+	return len(helper.Grid(data).FindShape(xShapedMAS))
 }
 
-func checkLeftDown(data [][]string, row, col int) int {
-	if row+3 >= len(data) || col-3 < 0 {
-		return 0
-	}
-	if data[row+1][col-1] != "M" {
-		return 0
-	}
-	if data[row+2][col-2] != "A" {
-		return 0
-	}
-	if data[row+3][col-3] != "S" {
-		return 0
-	}
-	return 1
-}
+// renderMatches prints the grid once for each part, showing only the letters that were counted
+func renderMatches(data [][]string, style wordsearch.Style) error {
+	grid := helper.Grid(data)
 
-func checkLeft(data [][]string, row, col int) int {
-	if col-3 < 0 {
-		return 0
-	}
-	if data[row][col-1] != "M" {
-		return 0
-	}
-	if data[row][col-2] != "A" {
-		return 0
+	fmt.Println("Part 1 matches:")
+	if err := wordsearch.Render(os.Stdout, grid, wordsearch.Find(grid, []string{"XMAS"}, wordsearch.Options{}), style); err != nil {
+		return err
 	}
-	if data[row][col-3] != "S" {
-		return 0
-	}
-	return 1
-}
 
-func checkLeftUp(data [][]string, row, col int) int {
-	if row-3 < 0 || col-3 < 0 {
-		return 0
-	}
-	if data[row-1][col-1] != "M" {
-		return 0
+	fmt.Println("Part 2 matches:")
+	var groups [][]helper.Position
+	for _, match := range grid.FindShape(xShapedMAS) {
+		groups = append(groups, match.Positions)
 	}
-	if data[row-2][col-2] != "A" {
-		return 0
-	}
-	if data[row-3][col-3] != "S" {
-		return 0
-	}
-	return 1
+	return wordsearch.RenderPositions(os.Stdout, grid, groups, style)
 }
 
-func checkUp(data [][]string, row, col int) int {
-	if row-3 < 0 {
-		return 0
-	}
-	if data[row-1][col] != "M" {
-		return 0
-	}
-	if data[row-2][col] != "A" {
-		return 0
-	}
-	if data[row-3][col] != "S" {
-		return 0
-	}
-	return 1
+var render = flag.String("render", "", "print the grid with only the matched letters shown instead of the answers (plain, ansi or html)")
+
+func init() {
+	runner.Register(4, 1, part1)
+	runner.Register(4, 2, part2)
 }
 
-func checkRightUp(data [][]string, row, col int) int {
-	if row-3 < 0 || col+3 >= len(data[row]) {
-		return 0
-	}
-	if data[row-1][col+1] != "M" {
-		return 0
-	}
-	if data[row-2][col+2] != "A" {
-		return 0
-	}
-	if data[row-3][col+3] != "S" {
-		return 0
-	}
-	return 1
+func part1(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	return findXMAS(getInputData(inputPath)), nil
 }
 
-func findXMAS(data [][]string) int {
-	total := 0
-	for i, row := range data {
-		for j := range row {
-			if data[i][j] == "X" {
-				total += checkRight(data, i, j)
-				total += checkRightDown(data, i, j)
-				total += checkDown(data, i, j)
-				total += checkLeftDown(data, i, j)
-				total += checkLeft(data, i, j)
-				total += checkLeftUp(data, i, j)
-				total += checkUp(data, i, j)
-				total += checkRightUp(data, i, j)
-			}
-		}
-	}
-	return total
+func part2(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	return findXshapedMAS(getInputData(inputPath)), nil
 }
 
-func findXshapedMAS(data [][]string) int {
-	total := 0
-	for i, row := range data {
-		if i+2 >= len(data) {
-			break
+func main() {
+	flag.Parse()
+	if *render != "" {
+		style, err := wordsearch.ParseStyle(*render)
+		if err == nil {
+			err = renderMatches(getInputData(runner.Input(4)), style)
 		}
-		for j := range row {
-			if j+2 >= len(data[i]) {
-				break
-			}
-			if data[i+1][j+1] != "A" {
-				continue
-			}
-			// M Top Left
-			if data[i][j] == "M" {
-				if data[i+2][j+2] != "S" {
-					continue
-				}
-				// M Top Right
-				if data[i][j+2] == "M" {
-					if data[i+2][j] != "S" {
-						continue
-					}
-					total++
-				}
-				// M Bottom Left
-				if data[i+2][j] == "M" {
-					if data[i][j+2] != "S" {
-						continue
-					}
-					total++
-				}
-			}
-			// M Bottom Right
-			if data[i+2][j+2] == "M" {
-				if data[i][j] != "S" {
-					continue
-				}
-				// M Top Right
-				if data[i][j+2] == "M" {
-					if data[i+2][j] != "S" {
-						continue
-					}
-					total++
-				}
-				// M Bottom Left
-				if data[i+2][j] == "M" {
-					if data[i][j+2] != "S" {
-						continue
-					}
-					total++
-				}
-			}
+		if err != nil {
+			log.Fatal(err)
 		}
+		return
 	}
-	return total
-}
-
-func main() {
-	data := getInputData()
-	fmt.Println("Day 4 - Part 1:", findXMAS(data))
-	fmt.Println("Day 4 - Part 2:", findXshapedMAS(data))
+	runner.Main()
 }
@@ -0,0 +1,117 @@
+package wordsearch
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+
+	"advent-of-code-2024/helper"
+)
+
+// Style selects how Render shows the grid
+type Style int
+
+const (
+	Plain Style = iota // Matched letters kept, everything else replaced with "."
+	ANSI               // Like Plain, with each match in its own terminal colour
+	HTML               // Like ANSI, as a <pre> block with coloured spans
+)
+
+// ParseStyle converts a style name (plain, ansi or html) to a Style
+func ParseStyle(name string) (Style, error) {
+	switch name {
+	case "plain":
+		return Plain, nil
+	case "ansi":
+		return ANSI, nil
+	case "html":
+		return HTML, nil
+	}
+	return Plain, fmt.Errorf("unknown render style '%s'", name)
+}
+
+// Render writes the grid with only the letters of the matches kept, in the style of the puzzle's examples
+func Render(w io.Writer, grid helper.Grid, matches []Match, style Style) error {
+	groups := make([][]helper.Position, len(matches))
+	for i, match := range matches {
+		groups[i] = match.Positions
+	}
+	return RenderPositions(w, grid, groups, style)
+}
+
+// RenderPositions is like Render for any groups of cells, such as shape matches.
+// Each group is one match; a cell in several groups takes the colour of the first.
+func RenderPositions(w io.Writer, grid helper.Grid, groups [][]helper.Position, style Style) error {
+	owner := make(map[helper.Position]int)
+	for i, group := range groups {
+		for _, pos := range group {
+			if _, ok := owner[pos]; !ok {
+				owner[pos] = i
+			}
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	if style == HTML {
+		fmt.Fprintln(out, `<pre class="wordsearch">`)
+	}
+	for row := range grid {
+		for col := range grid[row] {
+			pos := helper.Position{Row: row, Col: col}
+			group, matched := owner[pos]
+			if !matched {
+				fmt.Fprint(out, ".")
+				continue
+			}
+			letter := grid.Get(pos)
+			switch style {
+			case Plain:
+				fmt.Fprint(out, letter)
+			case ANSI:
+				r, g, b := matchColour(group)
+				fmt.Fprintf(out, "\033[1;38;2;%d;%d;%dm%s\033[0m", r, g, b, letter)
+			case HTML:
+				r, g, b := matchColour(group)
+				fmt.Fprintf(out, `<span style="color:#%02x%02x%02x">%s</span>`, r, g, b, html.EscapeString(letter))
+			}
+		}
+		fmt.Fprintln(out)
+	}
+	if style == HTML {
+		fmt.Fprintln(out, "</pre>")
+	}
+	return out.Flush()
+}
+
+// matchColour picks a colour for the nth match. Hues are spaced by the golden angle so neighbouring
+// matches always look different, however many there are.
+func matchColour(n int) (int, int, int) {
+	hue := math.Mod(float64(n)*137.508, 360)
+	return hslToRGB(hue, 0.75, 0.55)
+}
+
+// hslToRGB converts a hue in degrees and saturation and lightness in [0, 1] to 8-bit RGB
+func hslToRGB(hue, saturation, lightness float64) (int, int, int) {
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	m := lightness - chroma/2
+
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g, b = chroma, x, 0
+	case hue < 120:
+		r, g, b = x, chroma, 0
+	case hue < 180:
+		r, g, b = 0, chroma, x
+	case hue < 240:
+		r, g, b = 0, x, chroma
+	case hue < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return int(math.Round((r + m) * 255)), int(math.Round((g + m) * 255)), int(math.Round((b + m) * 255))
+}
@@ -0,0 +1,284 @@
+package wordsearch
+
+import (
+	"sort"
+
+	"advent-of-code-2024/helper"
+)
+
+// Direction is one of the eight directions a word can be read in
+type Direction int
+
+const (
+	Right Direction = iota
+	RightDown
+	Down
+	LeftDown
+	Left
+	LeftUp
+	Up
+	RightUp
+)
+
+// AllDirections lists every direction, clockwise from Right
+var AllDirections = []Direction{Right, RightDown, Down, LeftDown, Left, LeftUp, Up, RightUp}
+
+var steps = [...]helper.Position{
+	Right:     {Row: 0, Col: 1},
+	RightDown: {Row: 1, Col: 1},
+	Down:      {Row: 1, Col: 0},
+	LeftDown:  {Row: 1, Col: -1},
+	Left:      {Row: 0, Col: -1},
+	LeftUp:    {Row: -1, Col: -1},
+	Up:        {Row: -1, Col: 0},
+	RightUp:   {Row: -1, Col: 1},
+}
+
+var directionNames = [...]string{
+	Right:     "Right",
+	RightDown: "RightDown",
+	Down:      "Down",
+	LeftDown:  "LeftDown",
+	Left:      "Left",
+	LeftUp:    "LeftUp",
+	Up:        "Up",
+	RightUp:   "RightUp",
+}
+
+// Step returns the row and column offset of one move in the direction
+func (d Direction) Step() (int, int) {
+	return steps[d].Row, steps[d].Col
+}
+
+func (d Direction) String() string {
+	return directionNames[d]
+}
+
+// MarshalText lets directions appear by name in JSON output
+func (d Direction) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Options controls how the grid is searched
+type Options struct {
+	Directions []Direction // Directions to read words in, all eight if empty
+	Wrap       bool        // Whether words may run off one edge of the grid and continue from the opposite edge
+}
+
+// Match is one occurrence of a word in the grid
+type Match struct {
+	Word      string
+	Start     helper.Position
+	Direction Direction
+	Positions []helper.Position // The cell of each letter of the word, in order
+}
+
+// Find returns every occurrence of the words in the grid, ordered by start position, then direction, then word.
+// All words are searched for in a single pass over each line of the grid using an Aho-Corasick automaton.
+func Find(grid helper.Grid, words []string, opts Options) []Match {
+	directions := opts.Directions
+	if len(directions) == 0 {
+		directions = AllDirections
+	}
+
+	automaton := newAutomaton(words)
+	if len(automaton.words) == 0 {
+		return nil
+	}
+
+	var matches []Match
+	for _, direction := range directions {
+		for _, line := range gridLines(grid, direction, opts.Wrap) {
+			matches = append(matches, automaton.search(grid, line, direction, opts.Wrap)...)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Start != b.Start {
+			if a.Start.Row != b.Start.Row {
+				return a.Start.Row < b.Start.Row
+			}
+			return a.Start.Col < b.Start.Col
+		}
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		return a.Word < b.Word
+	})
+	return matches
+}
+
+// Count returns the number of occurrences of the words in the grid
+func Count(grid helper.Grid, words []string, opts Options) int {
+	return len(Find(grid, words, opts))
+}
+
+// gridLines returns the cells of every line through the grid in a direction.
+// Without wrapping a line starts where stepping backwards would leave the grid; with wrapping
+// every line is a cycle, returned once starting from its first cell in row-major order.
+func gridLines(grid helper.Grid, direction Direction, wrap bool) [][]helper.Position {
+	dr, dc := direction.Step()
+	height, width := grid.Height(), grid.Width()
+	var lines [][]helper.Position
+
+	if !wrap {
+		for row := 0; row < height; row++ {
+			for col := 0; col < width; col++ {
+				start := helper.Position{Row: row, Col: col}
+				if grid.IsInBounds(start.Add(-dr, -dc)) {
+					continue
+				}
+				var line []helper.Position
+				for pos := start; grid.IsInBounds(pos); pos = pos.Add(dr, dc) {
+					line = append(line, pos)
+				}
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	visited := make(map[helper.Position]bool)
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			start := helper.Position{Row: row, Col: col}
+			if visited[start] {
+				continue
+			}
+			var line []helper.Position
+			for pos := start; !visited[pos]; pos = wrapPosition(pos.Add(dr, dc), height, width) {
+				visited[pos] = true
+				line = append(line, pos)
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// wrapPosition moves a position that has stepped off the grid back onto the opposite edge
+func wrapPosition(pos helper.Position, height, width int) helper.Position {
+	return helper.Position{
+		Row: (pos.Row%height + height) % height,
+		Col: (pos.Col%width + width) % width,
+	}
+}
+
+// automaton is an Aho-Corasick automaton over grid cells, one letter per cell
+type automaton struct {
+	words   []string
+	lengths []int // Length of each word in letters
+	longest int
+	next    []map[string]int
+	fail    []int
+	outputs [][]int // Indices of the words that end at each state, including through fail links
+}
+
+func newAutomaton(words []string) *automaton {
+	a := &automaton{
+		next:    []map[string]int{{}},
+		fail:    []int{0},
+		outputs: [][]int{nil},
+	}
+
+	// Build the trie, skipping empty and repeated words
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+
+		letters := splitLetters(word)
+		state := 0
+		for _, letter := range letters {
+			child, ok := a.next[state][letter]
+			if !ok {
+				child = len(a.next)
+				a.next = append(a.next, map[string]int{})
+				a.fail = append(a.fail, 0)
+				a.outputs = append(a.outputs, nil)
+				a.next[state][letter] = child
+			}
+			state = child
+		}
+		a.outputs[state] = append(a.outputs[state], len(a.words))
+		a.words = append(a.words, word)
+		a.lengths = append(a.lengths, len(letters))
+		a.longest = helper.Max(a.longest, len(letters))
+	}
+
+	// Set fail links breadth first so each state's fail target is finished before its children
+	queue := []int{}
+	for _, child := range a.next[0] {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for letter, child := range a.next[state] {
+			a.fail[child] = a.step(a.fail[state], letter)
+			a.outputs[child] = append(a.outputs[child], a.outputs[a.fail[child]]...)
+			queue = append(queue, child)
+		}
+	}
+	return a
+}
+
+// step follows the transition for a letter, falling back through fail links when there isn't one
+func (a *automaton) step(state int, letter string) int {
+	for {
+		if child, ok := a.next[state][letter]; ok {
+			return child
+		}
+		if state == 0 {
+			return 0
+		}
+		state = a.fail[state]
+	}
+}
+
+// search runs the automaton along a line. A wrapped line is a cycle, so it is read around once more
+// (up to the longest word) to catch words that cross its starting cell, and only words starting
+// within the first pass are reported.
+func (a *automaton) search(grid helper.Grid, line []helper.Position, direction Direction, wrap bool) []Match {
+	length := len(line)
+	if wrap {
+		length += a.longest - 1
+	}
+
+	var matches []Match
+	state := 0
+	for i := 0; i < length; i++ {
+		state = a.step(state, grid.Get(line[i%len(line)]))
+		for _, w := range a.outputs[state] {
+			wordLength := a.lengths[w]
+			start := i - wordLength + 1
+			// A word longer than a wrapped line would reuse its own cells
+			if start >= len(line) || wordLength > len(line) {
+				continue
+			}
+			positions := make([]helper.Position, wordLength)
+			for k := range positions {
+				positions[k] = line[(start+k)%len(line)]
+			}
+			matches = append(matches, Match{
+				Word:      a.words[w],
+				Start:     positions[0],
+				Direction: direction,
+				Positions: positions,
+			})
+		}
+	}
+	return matches
+}
+
+// splitLetters splits a word into one string per rune, matching the cells of a Grid
+func splitLetters(word string) []string {
+	var letters []string
+	for _, r := range word {
+		letters = append(letters, string(r))
+	}
+	return letters
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"testing"
+
+	"advent-of-code-2024/day05/pageorder"
+	"advent-of-code-2024/runner"
+)
+
+// The original implementation scanned the full rule list for every page, kept here as the baseline for the benchmarks
+
+func scanGetRule(rules [][]int, value int) []int {
+	ruleValues := []int{}
+	for _, rule := range rules {
+		if rule[0] == value {
+			ruleValues = append(ruleValues, rule[1])
+		}
+	}
+	return ruleValues
+}
+
+func scanCheckRule(values []int, rule int) bool {
+	for _, value := range values {
+		if value == rule {
+			return false
+		}
+	}
+	return true
+}
+
+func scanCheckOrder(values []int, rules [][]int) int {
+	for j, value := range values {
+		ruleValues := scanGetRule(rules, value)
+		before := values[:j]
+		for _, rule := range ruleValues {
+			if !scanCheckRule(before, rule) {
+				return rule
+			}
+		}
+	}
+	return -1
+}
+
+func scanMoveBrokenRuleValueToEnd(update []int, brokenRule int) []int {
+	updated := []int{}
+	for _, value := range update {
+		if value != brokenRule {
+			updated = append(updated, value)
+		}
+	}
+	updated = append(updated, brokenRule)
+	return updated
+}
+
+func scanMoveUntilCorrectOrder(update []int, rules [][]int) []int {
+	brokenRule := scanCheckOrder(update, rules)
+	if brokenRule == -1 {
+		return update
+	}
+	newUpdate := scanMoveBrokenRuleValueToEnd(update, brokenRule)
+	return scanMoveUntilCorrectOrder(newUpdate, rules)
+}
+
+func scanPart1(rules [][]int, updates [][]int) int {
+	total := 0
+	for _, update := range updates {
+		if scanCheckOrder(update, rules) == -1 {
+			total += getMiddleValue(update)
+		}
+	}
+	return total
+}
+
+func scanPart2(rules [][]int, updates [][]int) int {
+	total := 0
+	for _, update := range updates {
+		if scanCheckOrder(update, rules) == -1 {
+			continue
+		}
+		total += getMiddleValue(scanMoveUntilCorrectOrder(update, rules))
+	}
+	return total
+}
+
+// sortedPart2 solves part 2 by sorting each broken update with the RuleSet comparator
+func sortedPart2(rules *pageorder.RuleSet, updates [][]int) int {
+	total := 0
+	for _, update := range updates {
+		if rules.IsOrdered(update) {
+			continue
+		}
+		sorted := append([]int(nil), update...)
+		sort.SliceStable(sorted, rules.Less(sorted))
+		total += getMiddleValue(sorted)
+	}
+	return total
+}
+
+func BenchmarkScanPart1(b *testing.B) {
+	benchmark(b, func(rulePairs, updates [][]int) { scanPart1(rulePairs, updates) })
+}
+
+func BenchmarkRuleSetPart1(b *testing.B) {
+	benchmark(b, func(rulePairs, updates [][]int) { day05_1(pageorder.NewRuleSet(rulePairs), updates) })
+}
+
+func BenchmarkScanPart2(b *testing.B) {
+	benchmark(b, func(rulePairs, updates [][]int) { scanPart2(rulePairs, updates) })
+}
+
+// BenchmarkRuleSetPart2 orders the broken updates with a topological sort, as the solution does
+func BenchmarkRuleSetPart2(b *testing.B) {
+	benchmark(b, func(rulePairs, updates [][]int) {
+		if _, err := day05_2(pageorder.NewRuleSet(rulePairs), updates); err != nil {
+			b.Fatal(err)
+		}
+	})
+}
+
+// BenchmarkSliceStablePart2 orders the broken updates with sort.SliceStable and the RuleSet comparator instead
+func BenchmarkSliceStablePart2(b *testing.B) {
+	benchmark(b, func(rulePairs, updates [][]int) { sortedPart2(pageorder.NewRuleSet(rulePairs), updates) })
+}
+
+// benchmark times a solution on the real puzzle input. Building the RuleSet is part of what's timed,
+// parsing the input isn't.
+func benchmark(b *testing.B, solve func(rulePairs, updates [][]int)) {
+	inputPath := runner.InputPath(5)
+	if _, err := os.Stat(inputPath); err != nil {
+		b.Skipf("no puzzle input: %v", err)
+	}
+	rulePairs, updates, err := getInputData(inputPath)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		solve(rulePairs, updates)
+	}
+}
@@ -1,80 +1,75 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"log"
+	"log/slog"
 	"os"
-	"strconv"
 	"strings"
-)
 
-func getInputData() ([][]int, [][]int) {
-	rules := [][]int{}
-	updates := [][]int{}
+	"advent-of-code-2024/day05/pageorder"
+	"advent-of-code-2024/helper"
+	"advent-of-code-2024/runner"
+)
 
-	file, err := os.Open("input.txt")
+func getInputData(inputPath string) ([][]int, [][]int, error) {
+	ruleLines, updateLines, err := helper.ReadTwoSections(inputPath)
 	if err != nil {
-		panic(err)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.Contains(line, "|") {
-			// Rule line
-			parts := strings.Split(line, "|")
-			rule := make([]int, 2)
-			rule[0], _ = strconv.Atoi(parts[0])
-			rule[1], _ = strconv.Atoi(parts[1])
-			rules = append(rules, rule)
-		} else if line != "" {
-			// Value line
-			parts := strings.Split(line, ",")
-			update := make([]int, len(parts))
-			for i, p := range parts {
-				update[i], _ = strconv.Atoi(p)
-			}
-			updates = append(updates, update)
-		}
+		return nil, nil, err
 	}
 
-	if err := scanner.Err(); err != nil {
-		panic(err)
+	rules := make([][]int, len(ruleLines))
+	for i, line := range ruleLines {
+		if rules[i], err = parseRule(line); err != nil {
+			return nil, nil, err
+		}
 	}
-	return rules, updates
-}
 
-func getRule(rules [][]int, value int) []int {
-	ruleValues := []int{}
-	for _, rule := range rules {
-		if rule[0] == value {
-			ruleValues = append(ruleValues, rule[1])
+	updates := make([][]int, len(updateLines))
+	for i, line := range updateLines {
+		if updates[i], err = parseUpdate(line); err != nil {
+			return nil, nil, err
 		}
 	}
-	return ruleValues
+	return rules, updates, nil
 }
 
-func checkRule(values []int, rule int) bool {
-	for _, value := range values {
-		if value == rule {
-			return false
-		}
+// parseRule parses an X|Y rule line
+func parseRule(line helper.Line) ([]int, error) {
+	parts := strings.Split(line.Text, "|")
+	if len(parts) != 2 {
+		return nil, helper.NewLineError(line, "expected a rule in the form X|Y")
+	}
+	rule, err := helper.StringsToInts(parts)
+	if err != nil {
+		return nil, &helper.LineError{Line: line, Err: err}
 	}
-	return true
+	if rule[0] == rule[1] {
+		return nil, helper.NewLineError(line, "page %d can't be ordered before itself", rule[0])
+	}
+	return rule, nil
 }
 
-func checkOrder(values []int, rules [][]int) int {
-	for j, value := range values {
-		ruleValues := getRule(rules, value)
-		before := values[:j]
-		for _, rule := range ruleValues {
-			if !checkRule(before, rule) {
-				return rule
-			}
+// parseUpdate parses a comma separated update line. Updates need a middle page, so their length must be odd.
+func parseUpdate(line helper.Line) ([]int, error) {
+	update, err := helper.StringsToInts(strings.Split(line.Text, ","))
+	if err != nil {
+		return nil, &helper.LineError{Line: line, Err: err}
+	}
+	if helper.IsEven(len(update)) {
+		return nil, helper.NewLineError(line, "update has %d pages, it needs an odd number to have a middle page", len(update))
+	}
+	seen := make(map[int]bool, len(update))
+	for _, page := range update {
+		if seen[page] {
+			return nil, helper.NewLineError(line, "page %d appears more than once", page)
 		}
+		seen[page] = true
 	}
-	return -1
+	return update, nil
 }
 
 func getMiddleValue(update []int) int {
@@ -83,50 +78,100 @@ func getMiddleValue(update []int) int {
 	return update[middleIndex]
 }
 
-func day05_1(rules [][]int, values [][]int) int {
+func day05_1(rules *pageorder.RuleSet, values [][]int) int {
 	total := 0
 	for _, update := range values {
-		if checkOrder(update, rules) == -1 {
+		if rules.IsOrdered(update) {
 			total += getMiddleValue(update)
 		}
 	}
 	return total
 }
 
-func moveBrokenRuleValueToEnd(update []int, brokenRule int) []int {
-	updated := []int{}
-	for _, value := range update {
-		if value != brokenRule {
-			updated = append(updated, value)
+func day05_2(rules *pageorder.RuleSet, values [][]int) (int, error) {
+	total := 0
+	for _, update := range values {
+		if rules.IsOrdered(update) {
+			continue
 		}
+		ordered, err := rules.Order(update)
+		if err != nil {
+			return 0, fmt.Errorf("update %v: %w", update, err)
+		}
+		total += getMiddleValue(ordered)
 	}
-	updated = append(updated, brokenRule)
-	return updated
+	return total, nil
 }
 
-func moveUntilCorrectOrder(update []int, rules [][]int) []int {
-	brokenRule := checkOrder(update, rules)
-	if brokenRule == -1 {
-		return update
+// printReports writes a validation report for every update, as JSON or as text explaining each rejected update
+func printReports(rules *pageorder.RuleSet, updates [][]int, format string) error {
+	reports := make([]pageorder.Report, len(updates))
+	for i, update := range updates {
+		report, err := rules.Validate(update)
+		if err != nil {
+			return fmt.Errorf("update %d: %w", i+1, err)
+		}
+		reports[i] = report
 	}
-	newUpdate := moveBrokenRuleValueToEnd(update, brokenRule)
-	return moveUntilCorrectOrder(newUpdate, rules)
-}
 
-func day05_2(rules [][]int, values [][]int) int {
-	total := 0
-	for _, update := range values {
-		if checkOrder(update, rules) == -1 {
-			continue
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	case "text":
+		for i, report := range reports {
+			if report.Ordered {
+				continue
+			}
+			fmt.Printf("Update %d %v breaks %d rule(s):\n", i+1, report.Update, len(report.Violations))
+			for _, violation := range report.Violations {
+				fmt.Println("  ", violation)
+			}
+			for _, move := range report.Moves {
+				fmt.Println("   fix:", move)
+			}
+			fmt.Println("   fixed:", report.Fixed)
 		}
-		ordered := moveUntilCorrectOrder(update, rules)
-		total += getMiddleValue(ordered)
+		return nil
 	}
-	return total
+	return fmt.Errorf("unknown report format '%s'", format)
+}
+
+var report = flag.String("report", "", "print a validation report for every update instead of the answers (text or json)")
+
+func init() {
+	runner.Register(5, 1, part1)
+	runner.Register(5, 2, part2)
+}
+
+func part1(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	rulePairs, updates, err := getInputData(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return day05_1(pageorder.NewRuleSet(rulePairs), updates), nil
+}
+
+func part2(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	rulePairs, updates, err := getInputData(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return day05_2(pageorder.NewRuleSet(rulePairs), updates)
 }
 
 func main() {
-	rules, updates := getInputData()
-	fmt.Println("Day 05 - Part 1: Total of correctly ordered update's middle values =", day05_1(rules, updates))
-	fmt.Println("Day 05 - Part 2: Total of incorrectly ordered update's corrected middle values =", day05_2(rules, updates))
+	flag.Parse()
+	if *report != "" {
+		rulePairs, updates, err := getInputData(runner.Input(5))
+		if err != nil {
+			log.Fatalf("Error reading input: %v", err)
+		}
+		if err := printReports(pageorder.NewRuleSet(rulePairs), updates, *report); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	runner.Main()
 }
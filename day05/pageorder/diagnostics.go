@@ -0,0 +1,135 @@
+package pageorder
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Violation is a rule Before|After broken by an update, with the positions of both pages in the update
+type Violation struct {
+	Before      int `json:"before"`
+	After       int `json:"after"`
+	BeforeIndex int `json:"beforeIndex"`
+	AfterIndex  int `json:"afterIndex"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%d|%d: %d at index %d must come before %d at index %d",
+		v.Before, v.After, v.Before, v.BeforeIndex, v.After, v.AfterIndex)
+}
+
+// Move takes a page from its index in the original update to its index in the fixed update.
+// Removing every moved page and then reinserting them in order of To produces the fixed update.
+type Move struct {
+	Page int `json:"page"`
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func (m Move) String() string {
+	return fmt.Sprintf("move %d from index %d to index %d", m.Page, m.From, m.To)
+}
+
+// Report explains whether an update is correctly ordered and, if not, how to fix it
+type Report struct {
+	Update     []int       `json:"update"`
+	Ordered    bool        `json:"ordered"`
+	Violations []Violation `json:"violations"`
+	Moves      []Move      `json:"moves"`
+	Fixed      []int       `json:"fixed"`
+}
+
+// Violations returns every rule broken by the update, ordered by the position of the page that should come first
+func (r *RuleSet) Violations(update []int) []Violation {
+	index := indexPages(update)
+	violations := []Violation{}
+	for i, page := range update {
+		for after := range r.after[page] {
+			if j, ok := index[after]; ok && j < i {
+				violations = append(violations, Violation{Before: page, After: after, BeforeIndex: i, AfterIndex: j})
+			}
+		}
+	}
+	sort.Slice(violations, func(a, b int) bool {
+		if violations[a].BeforeIndex != violations[b].BeforeIndex {
+			return violations[a].BeforeIndex < violations[b].BeforeIndex
+		}
+		return violations[a].AfterIndex < violations[b].AfterIndex
+	})
+	return violations
+}
+
+// Validate builds a Report for the update. The moves are the fewest needed to reach the order
+// produced by Order; when the rules fully order the update's pages (as in the puzzle) that order is
+// the only valid one, so the moves are minimal overall. Pages must be distinct, as for Order.
+func (r *RuleSet) Validate(update []int) (Report, error) {
+	if page, ok := duplicatePage(update); ok {
+		return Report{}, fmt.Errorf("page %d appears more than once in the update", page)
+	}
+	report := Report{
+		Update:     update,
+		Violations: r.Violations(update),
+		Moves:      []Move{},
+	}
+	report.Ordered = len(report.Violations) == 0
+	if report.Ordered {
+		report.Fixed = update
+		return report, nil
+	}
+
+	fixed, err := r.Order(update)
+	if err != nil {
+		return report, err
+	}
+	report.Fixed = fixed
+
+	// The pages that can stay put are the longest run of the update that is already in fixed order
+	target := indexPages(fixed)
+	positions := make([]int, len(update))
+	for i, page := range update {
+		positions[i] = target[page]
+	}
+	for _, i := range notInLongestIncreasing(positions) {
+		report.Moves = append(report.Moves, Move{Page: update[i], From: i, To: positions[i]})
+	}
+	sort.Slice(report.Moves, func(a, b int) bool {
+		return report.Moves[a].To < report.Moves[b].To
+	})
+	return report, nil
+}
+
+// notInLongestIncreasing returns the indices of values left out of a longest strictly increasing subsequence
+func notInLongestIncreasing(values []int) []int {
+	// tails[k] is the index of the smallest tail of an increasing subsequence of length k+1
+	var tails []int
+	previous := make([]int, len(values))
+	for i, value := range values {
+		k := sort.Search(len(tails), func(k int) bool {
+			return values[tails[k]] >= value
+		})
+		previous[i] = -1
+		if k > 0 {
+			previous[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	keep := make([]bool, len(values))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i != -1; i = previous[i] {
+			keep[i] = true
+		}
+	}
+
+	var rest []int
+	for i := range values {
+		if !keep[i] {
+			rest = append(rest, i)
+		}
+	}
+	return rest
+}
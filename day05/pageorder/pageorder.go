@@ -0,0 +1,112 @@
+package pageorder
+
+import (
+	"fmt"
+
+	"advent-of-code-2024/helper"
+)
+
+// RuleSet holds the page ordering rules indexed both ways, so any pair of pages can be checked in O(1)
+type RuleSet struct {
+	before map[int]map[int]bool // before[x] holds every page that must be printed before x
+	after  map[int]map[int]bool // after[x] holds every page that must be printed after x
+}
+
+// NewRuleSet builds a RuleSet from X|Y pairs
+func NewRuleSet(pairs [][]int) *RuleSet {
+	r := &RuleSet{
+		before: make(map[int]map[int]bool),
+		after:  make(map[int]map[int]bool),
+	}
+	for _, pair := range pairs {
+		addToSet(r.after, pair[0], pair[1])
+		addToSet(r.before, pair[1], pair[0])
+	}
+	return r
+}
+
+// addToSet adds value to the set stored under key, creating the set if needed
+func addToSet(sets map[int]map[int]bool, key, value int) {
+	if sets[key] == nil {
+		sets[key] = make(map[int]bool)
+	}
+	sets[key][value] = true
+}
+
+// MustPrecede reports whether a rule says page a must be printed before page b
+func (r *RuleSet) MustPrecede(a, b int) bool {
+	return r.after[a][b]
+}
+
+// MustFollow reports whether a rule says page a must be printed after page b
+func (r *RuleSet) MustFollow(a, b int) bool {
+	return r.before[a][b]
+}
+
+// Less returns a comparator over the update for sort.SliceStable.
+// It only sorts correctly when the rules order every pair of pages in the update, as the puzzle input does;
+// use Order when the rules may leave pages unordered or contain a cycle.
+func (r *RuleSet) Less(update []int) func(i, j int) bool {
+	return func(i, j int) bool {
+		return r.MustPrecede(update[i], update[j])
+	}
+}
+
+// IsOrdered checks that no rule between pages of the update is broken.
+// Only the rules whose pages both appear in the update (the induced subgraph) are considered.
+func (r *RuleSet) IsOrdered(update []int) bool {
+	index := indexPages(update)
+	for i, page := range update {
+		for after := range r.after[page] {
+			if j, ok := index[after]; ok && j < i {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Order returns a copy of the update sorted so that every rule between its pages holds.
+// It topologically sorts the induced subgraph, so it runs in O(n + rules). Pages must be distinct, since
+// a repeated page can't be both before and after another. If the rules between the update's pages form
+// a cycle it returns an error wrapping a helper.CycleError with the pages on it.
+func (r *RuleSet) Order(update []int) ([]int, error) {
+	if page, ok := duplicatePage(update); ok {
+		return nil, fmt.Errorf("page %d appears more than once in the update", page)
+	}
+	index := indexPages(update)
+	ordered, err := helper.TopologicalSort(update, func(page int) []int {
+		var after []int
+		for next := range r.after[page] {
+			if _, ok := index[next]; ok {
+				after = append(after, next)
+			}
+		}
+		return after
+	})
+	if err != nil {
+		return nil, fmt.Errorf("page ordering rules can't all hold: %w", err)
+	}
+	return ordered, nil
+}
+
+// indexPages maps each page of an update to its position
+func indexPages(update []int) map[int]int {
+	index := make(map[int]int, len(update))
+	for i, page := range update {
+		index[page] = i
+	}
+	return index
+}
+
+// duplicatePage returns the first page that appears a second time in the update, or false if every page is distinct
+func duplicatePage(update []int) (int, bool) {
+	seen := make(map[int]bool, len(update))
+	for _, page := range update {
+		if seen[page] {
+			return page, true
+		}
+		seen[page] = true
+	}
+	return 0, false
+}
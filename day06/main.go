@@ -1,35 +1,16 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"log"
-	"os"
-)
+	"log/slog"
 
-func getInputData() [][]string {
-	file, err := os.Open("input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer file.Close()
-
-	var data [][]string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		var chars []string
-		for _, c := range line {
-			chars = append(chars, string(c))
-		}
-		data = append(data, chars)
-	}
-
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
+	"advent-of-code-2024/helper"
+	"advent-of-code-2024/runner"
+)
 
-	return data
+func getInputData(inputPath string) [][]string {
+	return helper.ReadInputToGrid(inputPath)
 }
 
 func findStartingPosition(data [][]string) (int, int) {
@@ -180,11 +161,15 @@ func copyData(data [][]string) [][]string {
 	return newData
 }
 
-func day06_2(data [][]string) int {
+// day06_2 tries an obstruction on every cell, so it checks between cells whether to give up
+func day06_2(ctx context.Context, data [][]string) (int, error) {
 	timeLoops := 0
 	startRow, startCol := findStartingPosition(data)
 	for i := range data {
 		for j := range data[i] {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
 			dataWithBlock := copyData(data) // Create a deep copy
 			dataWithBlock[i][j] = "#"
 			updatedData := moveUntilOutOfBounds(dataWithBlock, startRow, startCol, "N")
@@ -193,12 +178,22 @@ func day06_2(data [][]string) int {
 			}
 		}
 	}
-	return timeLoops
+	return timeLoops, nil
+}
+
+func init() {
+	runner.Register(6, 1, part1)
+	runner.Register(6, 2, part2)
+}
+
+func part1(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	return day06_1(getInputData(inputPath)), nil
+}
+
+func part2(ctx context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	return day06_2(ctx, getInputData(inputPath))
 }
 
 func main() {
-	data := getInputData()
-	fmt.Println("Day 06 - Part 1: Distinct Positions = ", day06_1(data))
-	data = getInputData()
-	fmt.Println("Day 06 - Part 2: Time Loops = ", day06_2(data))
+	runner.Main()
 }
@@ -2,46 +2,58 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"log/slog"
+	"math"
 	"os"
 	"strconv"
 	"strings"
+
+	"advent-of-code-2024/runner"
 )
 
-func getInput() ([]int, [][]int) {
-	file, err := os.Open("input.txt")
+func getInput(inputPath string) ([]int, [][]int, error) {
+	file, err := os.Open(inputPath) // #nosec G304
 	if err != nil {
-		panic(err)
+		return nil, nil, err
 	}
 	defer file.Close()
 
 	var results []int
 	var data [][]int
 	scanner := bufio.NewScanner(file)
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		line := scanner.Text()
 		if line == "" {
 			continue
 		}
 
-		// Split the line by colon and get the second part
+		// Split the line by colon into the test value and the numbers
 		parts := strings.Split(line, ":")
 		if len(parts) != 2 {
-			continue
+			return nil, nil, fmt.Errorf("line %d: expected '<result>: <numbers>', got '%s'", lineNumber, line)
 		}
 
 		// Add the first part to the results array
-		result, _ := strconv.Atoi(parts[0])
+		result, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: invalid result '%s': %v", lineNumber, parts[0], err)
+		}
 		results = append(results, result)
 
 		// Split the numbers by space and convert to integers
-		numStrs := strings.Fields(strings.TrimSpace(parts[1]))
+		numStrs := strings.Fields(parts[1])
+		if len(numStrs) == 0 {
+			return nil, nil, fmt.Errorf("line %d: no numbers after ':'", lineNumber)
+		}
 		numbers := make([]int, len(numStrs))
 		for i, numStr := range numStrs {
-			var num int
-			_, err := fmt.Sscanf(numStr, "%d", &num)
+			num, err := strconv.Atoi(numStr)
 			if err != nil {
-				continue
+				return nil, nil, fmt.Errorf("line %d: invalid number '%s': %v", lineNumber, numStr, err)
 			}
 			numbers[i] = num
 		}
@@ -49,120 +61,226 @@ func getInput() ([]int, [][]int) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		panic(err)
+		return nil, nil, err
 	}
 
-	return results, data
+	return results, data, nil
 }
 
-func generateAllPossibleOperations(numberOfOperations int) [][]byte {
-	// Calculate total number of possibilities (2^n)
-	total := 1 << numberOfOperations
-	operations := make([][]byte, total)
-
-	// Generate all possible combinations
-	for i := 0; i < total; i++ {
-		ops := make([]byte, numberOfOperations)
-		for j := 0; j < numberOfOperations; j++ {
-			// Check if jth bit is set in i
-			if (i & (1 << j)) != 0 {
-				ops[j] = 1
-			} else {
-				ops[j] = 0
-			}
-		}
-		operations[i] = ops
+// checkedAdd returns a + b, or false if the sum overflows an int
+func checkedAdd(a, b int) (int, bool) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, false
 	}
+	return sum, true
+}
 
-	return operations
+// checkedMul returns a * b, or false if the product overflows an int
+func checkedMul(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	if (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, false
+	}
+	product := a * b
+	if product/b != a {
+		return 0, false
+	}
+	return product, true
 }
 
-func generateAllPossibleOperations2(numberOfOperations int) [][]string {
-	// Calculate total number of possibilities (3^n)
+// checkedConcat returns the digits of a followed by the digits of b, or false if the result
+// overflows an int or b is negative (a minus sign can't appear in the middle of a number)
+func checkedConcat(a, b int) (int, bool) {
+	if b < 0 {
+		return 0, false
+	}
+	shift := 10
+	for shift <= b {
+		var ok bool
+		if shift, ok = checkedMul(shift, 10); !ok {
+			return 0, false
+		}
+	}
+	shifted, ok := checkedMul(a, shift)
+	if !ok {
+		return 0, false
+	}
+	if a < 0 {
+		return checkedAdd(shifted, -b)
+	}
+	return checkedAdd(shifted, b)
+}
+
+// combinations returns kinds^operators, the number of ways to choose the operators, or false if that overflows an int
+func combinations(kinds, operators int) (int, bool) {
 	total := 1
-	for i := 0; i < numberOfOperations; i++ {
-		total *= 3
+	for i := 0; i < operators; i++ {
+		var ok bool
+		if total, ok = checkedMul(total, kinds); !ok {
+			return 0, false
+		}
 	}
-	operations := make([][]string, total)
+	return total, true
+}
+
+// checkEvery is how many combinations of operators are tried between checks for cancellation
+const checkEvery = 1 << 16
 
-	// Generate all possible combinations
-	for i := 0; i < total; i++ {
-		ops := make([]string, numberOfOperations)
-		temp := i
-		for j := 0; j < numberOfOperations; j++ {
-			// Get value for this position (0, 1, or 2)
-			ops[j] = string('0' + byte(temp%3))
-			temp /= 3
+// operationsFor writes the operators of one combination into ops, where each bit of combination picks + (0) or * (1)
+func operationsFor(combination int, ops []byte) {
+	for j := range ops {
+		// Check if jth bit is set in combination
+		if (combination & (1 << j)) != 0 {
+			ops[j] = 1
+		} else {
+			ops[j] = 0
 		}
-		operations[i] = ops
 	}
+}
 
-	return operations
+// operationsFor2 writes the operators of one combination into ops, where each base 3 digit of combination
+// picks + ("0"), * ("1") or || ("2")
+func operationsFor2(combination int, ops []string) {
+	temp := combination
+	for j := range ops {
+		// Get value for this position (0, 1, or 2)
+		ops[j] = string('0' + byte(temp%3))
+		temp /= 3
+	}
 }
-func tryPossibleOperations(result int, data []int) bool {
+
+// tryPossibleOperations tries every combination of + and * (2^n of them) one at a time,
+// checking for cancellation every checkEvery combinations
+func tryPossibleOperations(ctx context.Context, result int, data []int) (bool, error) {
 	numberOfOperations := len(data) - 1
-	operations := generateAllPossibleOperations(numberOfOperations)
-	for _, operation := range operations {
+	total, ok := combinations(2, numberOfOperations)
+	if !ok {
+		return false, fmt.Errorf("%d operators have too many combinations to try", numberOfOperations)
+	}
+	operation := make([]byte, numberOfOperations)
+	for combination := 0; combination < total; combination++ {
+		if combination%checkEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+		}
+		operationsFor(combination, operation)
 		testResult := data[0]
+		ok := true
 		for i, op := range operation {
 			if op == 0 {
-				testResult += data[i+1]
+				testResult, ok = checkedAdd(testResult, data[i+1])
 			} else {
-				testResult *= data[i+1]
+				testResult, ok = checkedMul(testResult, data[i+1])
+			}
+			// An overflowed intermediate can't be trusted, so prune this combination
+			if !ok {
+				break
 			}
 		}
-		if testResult == result {
-			return true
+		if ok && testResult == result {
+			return true, nil
 		}
 	}
-	return false
+	return false, nil
 }
 
-func tryPossibleOperations2(result int, data []int) bool {
+// tryPossibleOperations2 tries every combination of +, * and || (3^n of them) one at a time,
+// checking for cancellation every checkEvery combinations
+func tryPossibleOperations2(ctx context.Context, result int, data []int) (bool, error) {
 	numberOfOperations := len(data) - 1
-	operations := generateAllPossibleOperations2(numberOfOperations)
-	for _, operation := range operations {
+	total, ok := combinations(3, numberOfOperations)
+	if !ok {
+		return false, fmt.Errorf("%d operators have too many combinations to try", numberOfOperations)
+	}
+	operation := make([]string, numberOfOperations)
+	for combination := 0; combination < total; combination++ {
+		if combination%checkEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+		}
+		operationsFor2(combination, operation)
 		testResult := data[0]
+		ok := true
 		for i, op := range operation {
 			if op == "0" {
-				testResult += data[i+1]
+				testResult, ok = checkedAdd(testResult, data[i+1])
 			} else if op == "1" {
-				testResult *= data[i+1]
+				testResult, ok = checkedMul(testResult, data[i+1])
 			} else {
-				strNum1 := strconv.Itoa(testResult)
-				strNum2 := strconv.Itoa(data[i+1])
-				testResult, _ = strconv.Atoi(strNum1 + strNum2)
+				testResult, ok = checkedConcat(testResult, data[i+1])
+			}
+			// An overflowed intermediate can't be trusted, so prune this combination
+			if !ok {
+				break
 			}
 		}
-		if testResult == result {
-			return true
+		if ok && testResult == result {
+			return true, nil
 		}
 	}
-	return false
+	return false, nil
 }
 
-func day07_1(results []int, data [][]int) int {
+func day07_1(ctx context.Context, results []int, data [][]int) (int, error) {
 	total := 0
 	for i, result := range results {
-		if tryPossibleOperations(result, data[i]) {
-			total += result
+		possible, err := tryPossibleOperations(ctx, result, data[i])
+		if err != nil {
+			return 0, fmt.Errorf("equation %d with test value %d: %w", i+1, result, err)
+		}
+		if possible {
+			var ok bool
+			if total, ok = checkedAdd(total, result); !ok {
+				return 0, fmt.Errorf("total overflows an int when adding %d from equation %d", result, i+1)
+			}
 		}
 	}
-	return total
+	return total, nil
 }
 
-func day07_2(results []int, data [][]int) int {
+func day07_2(ctx context.Context, results []int, data [][]int) (int, error) {
 	total := 0
 	for i, result := range results {
-		if tryPossibleOperations2(result, data[i]) {
-			total += result
+		possible, err := tryPossibleOperations2(ctx, result, data[i])
+		if err != nil {
+			return 0, fmt.Errorf("equation %d with test value %d: %w", i+1, result, err)
 		}
+		if possible {
+			var ok bool
+			if total, ok = checkedAdd(total, result); !ok {
+				return 0, fmt.Errorf("total overflows an int when adding %d from equation %d", result, i+1)
+			}
+		}
+	}
+	return total, nil
+}
+
+func init() {
+	runner.Register(7, 1, part1)
+	runner.Register(7, 2, part2)
+}
+
+func part1(ctx context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	results, data, err := getInput(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return day07_1(ctx, results, data)
+}
+
+func part2(ctx context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	results, data, err := getInput(inputPath)
+	if err != nil {
+		return nil, err
 	}
-	return total
+	return day07_2(ctx, results, data)
 }
 
 func main() {
-	results, data := getInput()
-	fmt.Println("Day 7 Part 1: Total of possibly valid results:", day07_1(results, data))
-	fmt.Println("Day 7 Part 2: Total of possibly valid results:", day07_2(results, data))
+	runner.Main()
 }
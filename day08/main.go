@@ -3,18 +3,26 @@ package main
 import (
 	"advent-of-code-2024/day08/antenna"
 	"advent-of-code-2024/helper"
-	"fmt"
+	"advent-of-code-2024/runner"
+	"context"
+	"log/slog"
 )
 
-func main() {
-	// Read and parse input
-	grid := helper.ReadInputToGrid("day08/input.txt")
+func init() {
+	runner.Register(8, 1, part1)
+	runner.Register(8, 2, part2)
+}
+
+// part1 finds antinodes based on distance ratios
+func part1(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	return antenna.FindAntinodes(helper.ReadInputToGrid(inputPath)), nil
+}
 
-	// Part 1: Find antinodes based on distance ratios
-	antinodeCount := antenna.FindAntinodes(grid)
-	fmt.Printf("Day 08 Part 1: Total Antinodes: %d\n", antinodeCount)
+// part2 finds antinodes considering resonant harmonics
+func part2(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	return antenna.FindAntinodesWithResonance(helper.ReadInputToGrid(inputPath)), nil
+}
 
-	// Part 2: Find antinodes considering resonant harmonics
-	resonantCount := antenna.FindAntinodesWithResonance(grid)
-	fmt.Printf("Day 08 Part 2: Total Antinodes with Resonance: %d\n", resonantCount)
+func main() {
+	runner.Main()
 }
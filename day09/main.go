@@ -1,15 +1,14 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"os"
-	"path/filepath"
+	"log/slog"
 	"sort"
 	"strconv"
-	"time"
 
 	"advent-of-code-2024/helper"
+	"advent-of-code-2024/runner"
 )
 
 // Block represents a contiguous section of the disk
@@ -19,8 +18,11 @@ type Block struct {
 }
 
 // CompactDisk moves file blocks from the end to the leftmost free space
-func CompactDisk(diskMap []string) []string {
+func CompactDisk(ctx context.Context, diskMap []string) ([]string, error) {
 	for i := len(diskMap) - 1; i >= 0; i-- { // Start from the rightmost block
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if diskMap[i] != "." { // If it's a file block
 			// Find the leftmost free space
 			for j := 0; j < i; j++ {
@@ -33,7 +35,7 @@ func CompactDisk(diskMap []string) []string {
 			}
 		}
 	}
-	return diskMap
+	return diskMap, nil
 }
 
 // CalculateChecksum computes the checksum of the disk map
@@ -51,7 +53,7 @@ func CalculateChecksum(diskMap []string) int {
 	return checksum
 }
 
-func CompactDiskPart2(diskMap []string) []string {
+func CompactDiskPart2(ctx context.Context, diskMap []string) ([]string, error) {
 	files := helper.IdentifyFiles(diskMap)
 	// Sort files by file ID descending
 	sort.Slice(files, func(i, j int) bool {
@@ -59,6 +61,9 @@ func CompactDiskPart2(diskMap []string) []string {
 	})
 
 	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		fileLen := f.End - f.Start + 1
 		// Attempt to find a free space span to the left of f.Start that can hold fileLen blocks
 		if f.Start > 0 {
@@ -69,7 +74,7 @@ func CompactDiskPart2(diskMap []string) []string {
 		}
 	}
 
-	return diskMap
+	return diskMap, nil
 }
 
 // findFreeSpaceToTheLeft searches within diskMap[start..end] for a contiguous run of '.' of length neededSize.
@@ -110,63 +115,50 @@ func moveFile(diskMap []string, oldStart, oldEnd, newStart int) {
 }
 
 func main() {
-	// Determine if we're running from the day directory or root
-	currentDir, err := os.Getwd()
-	if err != nil {
-		log.Fatalf("Error getting current directory: %v", err)
-	}
+	runner.Main()
+}
 
-	// Check if we're in the day09 directory
-	inDayDir := filepath.Base(currentDir) == "day09"
+func init() {
+	runner.Register(9, 1, part1)
+	runner.Register(9, 2, part2)
+}
 
-	// Adjust log directory path based on current location
-	logDir := "logs"
-	if !inDayDir {
-		logDir = "day09/logs"
+// getInputData reads and parses the disk map
+func getInputData(inputPath string, logger *slog.Logger) ([]string, error) {
+	input, err := helper.ReadInputAsString(inputPath)
+	if err != nil {
+		return nil, err
 	}
+	diskMap := helper.ParseDiskMap(input)
+	// The disk map has a block per entry, so it is only written out when debugging
+	logger.Debug("parsed disk map", "blocks", len(diskMap), "diskMap", diskMap)
+	return diskMap, nil
+}
 
-	// Create logs directory
-	if err := os.MkdirAll(logDir, 0750); err != nil {
-		log.Fatalf("Failed to create logs directory: %v", err)
+func part1(ctx context.Context, inputPath string, logger *slog.Logger) (any, error) {
+	diskMap, err := getInputData(inputPath, logger)
+	if err != nil {
+		return nil, err
 	}
-
-	// Create timestamped log file
-	timestamp := time.Now().Format("2006-01-02-150405")
-	logPath := filepath.Join(logDir, fmt.Sprintf("debug-%s.log", timestamp))
-
-	// Initialize logger
-	logFile, err := helper.InitLogger(logPath)
+	compactDiskPart1, err := CompactDisk(ctx, diskMap)
 	if err != nil {
-		log.Fatalf("Error initializing logger: %v", err)
+		return nil, err
 	}
-	defer logFile.Close()
+	checksumPart1 := CalculateChecksum(compactDiskPart1)
+	logger.Debug("compacted disk", "disk", compactDiskPart1)
+	return checksumPart1, nil
+}
 
-	// Try to read input file from either path
-	var input string
-	if inDayDir {
-		input, err = helper.ReadInputAsString("input.txt")
-	} else {
-		input, err = helper.ReadInputAsString("day09/input.txt")
+func part2(ctx context.Context, inputPath string, logger *slog.Logger) (any, error) {
+	diskMap, err := getInputData(inputPath, logger)
+	if err != nil {
+		return nil, err
 	}
+	compactDiskPart2, err := CompactDiskPart2(ctx, diskMap)
 	if err != nil {
-		log.Fatalf("Error reading input: %v", err)
+		return nil, err
 	}
-
-	// Parse the disk map
-	diskMap := helper.ParseDiskMap(input)
-	log.Printf("Parsed Disk Map: %v\n", diskMap)
-
-	// Part 1
-	compactDiskPart1 := CompactDisk(diskMap)
-	checksumPart1 := CalculateChecksum(compactDiskPart1)
-	log.Printf("Part 1 Compacted Disk: %v\n", compactDiskPart1)
-	log.Printf("Part 1 Checksum Calculation: %v\n", checksumPart1)
-	fmt.Printf("Day 09 Part 1: Checksum = %d\n", checksumPart1)
-
-	// Part 2
-	compactDiskPart2 := CompactDiskPart2(helper.ParseDiskMap(input)) // Re-parse to avoid mutating diskMap
 	checksumPart2 := CalculateChecksum(compactDiskPart2)
-	log.Printf("Part 2 Compacted Disk: %v\n", compactDiskPart2)
-	log.Printf("Part 2 Checksum Calculation: %v\n", checksumPart2)
-	fmt.Printf("Day 09 Part 2: Checksum = %d\n", checksumPart2)
+	logger.Debug("compacted disk", "disk", compactDiskPart2)
+	return checksumPart2, nil
 }
@@ -1,170 +1,161 @@
 package main
 
 import (
+	"advent-of-code-2024/day10/trails"
 	"advent-of-code-2024/helper"
+	"advent-of-code-2024/runner"
+	"context"
+	"flag"
 	"fmt"
+	"log"
+	"log/slog"
+	"math/big"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
 )
 
-func getInputData() [][]int {
-	currentDir, err := os.Getwd()
-	if err != nil {
-		fmt.Println("Error getting current directory:", err)
-	}
-	inDayDir := filepath.Base(currentDir) == "day10"
-	var data [][]int
-	if inDayDir {
-		data = helper.ReadInputToInt2DArray("input.txt")
-	} else {
-		data = helper.ReadInputToInt2DArray("day10/input.txt")
-	}
-	return data
+func getInputData(inputPath string) [][]int {
+	return helper.ReadInputToInt2DArrayWithBlanks(inputPath, trails.Impassable)
 }
 
-type cell struct {
-	r, c int
+func day10Part1(m *trails.Map) int {
+	return m.Score()
 }
 
-func day10Part1(grid [][]int) int {
-	rows := len(grid)
-	cols := len(grid[0])
+// day10Part2 sums the trailhead ratings using the given arithmetic
+func day10Part2[T any](m *trails.Map, counting helper.Counting[T], method helper.PathMethod) (T, error) {
+	return trails.Rating(m, counting, method)
+}
 
-	// Gather all height 9 cells
-	var nines []cell
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			if grid[r][c] == 9 {
-				nines = append(nines, cell{r, c})
-			}
-		}
+// parseStep parses a "min..max" or single height change flag value. Trails are counted over steps that
+// can never return to a cell, so a range must only climb or only descend: one that includes 0, or both
+// climbs and descents, is rejected.
+func parseStep(s string) (trails.StepRule, error) {
+	low, high, isRange := strings.Cut(s, "..")
+	if !isRange {
+		high = low
 	}
-
-	// Map each 9 cell to an ID
-	nineID := make(map[cell]int)
-	for i, pos := range nines {
-		nineID[pos] = i
+	min, minErr := strconv.Atoi(low)
+	max, maxErr := strconv.Atoi(high)
+	if minErr != nil || maxErr != nil {
+		return nil, fmt.Errorf("invalid step '%s', expected a height change like 1 or a range like 1..2", s)
 	}
-
-	// reachable[r][c] holds a boolean slice marking which 9s are reachable
-	reachable := make([][][]bool, rows)
-	for r := 0; r < rows; r++ {
-		reachable[r] = make([][]bool, cols)
-		for c := 0; c < cols; c++ {
-			reachable[r][c] = make([]bool, len(nines))
-		}
+	if min > max {
+		return nil, fmt.Errorf("invalid step '%s', the smallest change comes first", s)
 	}
-
-	// Initialize for height 9 cells
-	for i, pos := range nines {
-		reachable[pos.r][pos.c][i] = true
+	if min <= 0 && max >= 0 {
+		return nil, fmt.Errorf("unsupported step '%s': trails could loop between cells, so steps must all climb (e.g. 1..2) or all descend (e.g. -2..-1)", s)
 	}
+	return trails.StepRange(min, max), nil
+}
 
-	// Directions for neighbors
-	dirs := []cell{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
-
-	// Process heights from 8 down to 0
-	for h := 8; h >= 0; h-- {
-		for r := 0; r < rows; r++ {
-			for c := 0; c < cols; c++ {
-				if grid[r][c] == h {
-					// Union of all reachable sets from neighbors of height h+1
-					for _, d := range dirs {
-						nr, nc := r+d.r, c+d.c
-						if nr < 0 || nr >= rows || nc < 0 || nc >= cols {
-							continue
-						}
-						if grid[nr][nc] == h+1 {
-							for i := range reachable[nr][nc] {
-								if reachable[nr][nc][i] {
-									reachable[r][c][i] = true
-								}
-							}
-						}
-					}
-				}
-			}
-		}
+// parsePosition parses a "row,col" flag value
+func parsePosition(s string) (helper.Position, error) {
+	var pos helper.Position
+	if _, err := fmt.Sscanf(s, "%d,%d", &pos.Row, &pos.Col); err != nil {
+		return pos, fmt.Errorf("invalid position '%s', expected row,col: %v", s, err)
 	}
+	return pos, nil
+}
 
-	// Calculate the sum of scores for all trailheads (height 0)
-	totalScore := 0
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			if grid[r][c] == 0 {
-				count := 0
-				for i := range reachable[r][c] {
-					if reachable[r][c][i] {
-						count++
-					}
-				}
-				totalScore += count
-			}
-		}
+// showTrailhead prints the summits a trailhead reaches and its rating, its trails over the map, and optionally every trail
+func showTrailhead(m *trails.Map, start helper.Position, listPaths bool) error {
+	summits := m.Summits(start)
+	rating, err := trails.Counter[*big.Int](m, helper.BigCounting{}, helper.TopologicalOrder).Count(start)
+	if err != nil {
+		return err
 	}
-
-	return totalScore
+	fmt.Printf("Trailhead %v reaches %d summits with rating %v: %v\n", start, len(summits), rating, summits)
+	if err := m.Render(os.Stdout, start); err != nil {
+		return err
+	}
+	if listPaths {
+		count := 0
+		m.Paths(start, func(path []helper.Position) bool {
+			count++
+			fmt.Printf("Trail %d: %v\n", count, path)
+			return true
+		})
+	}
+	return nil
 }
 
-func day10Part2(grid [][]int) uint64 {
-	rows := len(grid)
-	cols := len(grid[0])
+var (
+	trailhead  = flag.String("trailhead", "", "show the trails from the trailhead at row,col instead of the answers")
+	listPaths  = flag.Bool("paths", false, "with -trailhead, also list every trail")
+	step       = flag.String("step", "1", "allowed height change per step, as a number or a min..max range that only climbs or only descends (negative)")
+	counting   = flag.String("count", "uint64", "how to count trails for part 2: uint64 (fails on overflow), mod or big")
+	modulus    = flag.Uint64("modulus", 1_000_000_007, "with -count mod, the modulus to count trails with")
+	methodName = flag.String("method", "topological", "how to count trails for part 2: topological or dfs")
+	rules      = trails.PuzzleRules()
+)
 
-	ways := make([][]uint64, rows)
-	for r := 0; r < rows; r++ {
-		ways[r] = make([]uint64, cols)
-	}
+func init() {
+	flag.IntVar(&rules.StartHeight, "start", rules.StartHeight, "height of trailheads")
+	flag.IntVar(&rules.EndHeight, "end", rules.EndHeight, "height of summits")
+	flag.BoolVar(&rules.Diagonal, "diagonal", false, "allow trails to move diagonally")
 
-	// Initialize for height 9 cells
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			if grid[r][c] == 9 {
-				ways[r][c] = 1
-			}
-		}
-	}
+	runner.Register(10, 1, part1)
+	runner.Register(10, 2, part2)
+}
 
-	dirs := []cell{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+// newMap reads the map with the trail rules given by the flags
+func newMap(inputPath string) (*trails.Map, error) {
+	return trails.New(getInputData(inputPath), rules)
+}
 
-	// Propagate downward: from height 8 to 0
-	for h := 8; h >= 0; h-- {
-		for r := 0; r < rows; r++ {
-			for c := 0; c < cols; c++ {
-				if grid[r][c] == h {
-					var total uint64 = 0
-					for _, d := range dirs {
-						nr, nc := r+d.r, c+d.c
-						if nr < 0 || nr >= rows || nc < 0 || nc >= cols {
-							continue
-						}
-						if grid[nr][nc] == h+1 {
-							total += ways[nr][nc]
-						}
-					}
-					ways[r][c] = total
-				}
-			}
-		}
+func part1(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	m, err := newMap(inputPath)
+	if err != nil {
+		return nil, err
 	}
+	return day10Part1(m), nil
+}
 
-	// Sum the ways for all trailheads (height 0)
-	var sumRatings uint64 = 0
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			if grid[r][c] == 0 {
-				sumRatings += ways[r][c]
-			}
+func part2(_ context.Context, inputPath string, _ *slog.Logger) (any, error) {
+	method, err := helper.ParsePathMethod(*methodName)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMap(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	// Ratings are counted with checked uint64, modulo a number, or exactly with big.Int
+	switch *counting {
+	case "uint64":
+		return day10Part2(m, helper.Uint64Counting{}, method)
+	case "mod":
+		if *modulus == 0 {
+			return nil, fmt.Errorf("modular counting needs a modulus of at least 1")
 		}
+		return day10Part2(m, helper.ModularCounting{Modulus: *modulus}, method)
+	case "big":
+		return day10Part2(m, helper.BigCounting{}, method)
 	}
-
-	return sumRatings
+	return nil, fmt.Errorf("unknown counting mode '%s', expected uint64, mod or big", *counting)
 }
 
 func main() {
-	data := getInputData()
-	result := day10Part1(data)
-	fmt.Println("Day 10 Part 1:", result)
-	result2 := day10Part2(data)
-	fmt.Println("Day 10 Part 2:", result2)
-
+	flag.Parse()
+	var err error
+	if rules.Step, err = parseStep(*step); err != nil {
+		log.Fatal(err)
+	}
+	if *trailhead != "" {
+		m, err := newMap(runner.Input(10))
+		if err != nil {
+			log.Fatal(err)
+		}
+		start, err := parsePosition(*trailhead)
+		if err == nil {
+			err = showTrailhead(m, start, *listPaths)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	runner.Main()
 }
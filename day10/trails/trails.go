@@ -0,0 +1,272 @@
+package trails
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"advent-of-code-2024/helper"
+)
+
+// Impassable marks a cell no trail can enter, shown as "." in the puzzle examples
+const Impassable = math.MinInt
+
+var (
+	orthogonal = []helper.Position{{Row: 1, Col: 0}, {Row: -1, Col: 0}, {Row: 0, Col: 1}, {Row: 0, Col: -1}}
+	diagonal   = []helper.Position{{Row: 1, Col: 1}, {Row: 1, Col: -1}, {Row: -1, Col: 1}, {Row: -1, Col: -1}}
+)
+
+// StepRule decides whether a trail may move from a cell of one height to a neighbouring cell of another
+type StepRule func(from, to int) bool
+
+// StepRange allows steps that change the height by between min and max, inclusive.
+// Negative changes are descents, so StepRange(1, 1) is the puzzle's rule and StepRange(-1, -1) walks it backwards.
+func StepRange(min, max int) StepRule {
+	return func(from, to int) bool {
+		change := to - from
+		return change >= min && change <= max
+	}
+}
+
+// Rules describes what counts as a hiking trail
+type Rules struct {
+	StartHeight int      // Height of trailheads
+	EndHeight   int      // Height of summits, where trails end
+	Step        StepRule // Which moves between neighbouring cells are allowed
+	Diagonal    bool     // Whether trails may also move diagonally
+}
+
+// PuzzleRules are the puzzle's rules: from height 0 to 9, climbing exactly one per step, without diagonals
+func PuzzleRules() Rules {
+	return Rules{StartHeight: 0, EndHeight: 9, Step: StepRange(1, 1)}
+}
+
+// Map is a topographic map with the summits reachable from every cell worked out up front
+type Map struct {
+	heights     [][]int
+	rules       Rules
+	directions  []helper.Position
+	summits     []helper.Position
+	summitIndex map[helper.Position]int
+	reachable   [][]helper.Bitset // reachable[r][c] holds the indices of the summits reachable from (r, c)
+}
+
+// Trailhead is a trailhead with the summits it can reach. Its score is the number of summits.
+type Trailhead struct {
+	Position helper.Position
+	Summits  []helper.Position
+}
+
+// New builds a Map from a grid of heights, which may include Impassable cells.
+// Trails are counted as paths through the steps, which is only finite if no trail can return to a cell it
+// has visited, so the step rule must rule out loops: a rule that only climbs or only descends always does.
+// A rule allowing level steps, or both climbs and descents, is rejected if the map has a loop for it.
+func New(heights [][]int, rules Rules) (*Map, error) {
+	m := &Map{heights: heights, rules: rules, directions: orthogonal, summitIndex: make(map[helper.Position]int)}
+	if rules.Diagonal {
+		m.directions = append(append([]helper.Position{}, orthogonal...), diagonal...)
+	}
+	var cells []helper.Position
+	m.forEachCell(func(pos helper.Position) {
+		if m.passable(pos) {
+			cells = append(cells, pos)
+		}
+		if m.height(pos) == rules.EndHeight {
+			m.summitIndex[pos] = len(m.summits)
+			m.summits = append(m.summits, pos)
+		}
+	})
+
+	m.reachable = make([][]helper.Bitset, len(heights))
+	for r := range heights {
+		m.reachable[r] = make([]helper.Bitset, len(heights[r]))
+	}
+
+	// Working backwards through a topological order of the steps means each cell's
+	// next steps are finished before the cell itself
+	order, err := helper.TopologicalSort(cells, m.nextSteps)
+	if err != nil {
+		var cycle helper.CycleError[helper.Position]
+		if errors.As(err, &cycle) {
+			return nil, fmt.Errorf("step rule lets trails loop, for example through %v at height %d, and looping trails can't be counted; use a rule that only climbs or only descends",
+				cycle.Cycle[0], m.height(cycle.Cycle[0]))
+		}
+		return nil, err
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		pos := order[i]
+		reachable := helper.NewBitset(len(m.summits))
+		if summit, ok := m.summitIndex[pos]; ok {
+			reachable.Set(summit)
+		} else {
+			for _, next := range m.nextSteps(pos) {
+				reachable.Union(m.reachable[next.Row][next.Col])
+			}
+		}
+		m.reachable[pos.Row][pos.Col] = reachable
+	}
+	return m, nil
+}
+
+// height returns the height at a position, or Impassable if it is off the map
+func (m *Map) height(pos helper.Position) int {
+	if pos.Row < 0 || pos.Row >= len(m.heights) || pos.Col < 0 || pos.Col >= len(m.heights[pos.Row]) {
+		return Impassable
+	}
+	return m.heights[pos.Row][pos.Col]
+}
+
+// passable checks if a trail can enter a position
+func (m *Map) passable(pos helper.Position) bool {
+	return m.height(pos) != Impassable
+}
+
+func (m *Map) forEachCell(fn func(pos helper.Position)) {
+	for r := range m.heights {
+		for c := range m.heights[r] {
+			fn(helper.Position{Row: r, Col: c})
+		}
+	}
+}
+
+// nextSteps returns the neighbours a trail can continue to from a position. Trails end at summits.
+func (m *Map) nextSteps(pos helper.Position) []helper.Position {
+	if !m.passable(pos) {
+		return nil
+	}
+	if _, ok := m.summitIndex[pos]; ok {
+		return nil
+	}
+	var next []helper.Position
+	for _, d := range m.directions {
+		neighbour := pos.Add(d.Row, d.Col)
+		if m.passable(neighbour) && m.rules.Step(m.height(pos), m.height(neighbour)) {
+			next = append(next, neighbour)
+		}
+	}
+	return next
+}
+
+// Trailheads returns every trailhead in row-major order
+func (m *Map) Trailheads() []Trailhead {
+	var trailheads []Trailhead
+	for _, pos := range m.trailheads() {
+		trailheads = append(trailheads, Trailhead{Position: pos, Summits: m.Summits(pos)})
+	}
+	return trailheads
+}
+
+// Summits returns the summits reachable from a position, in row-major order
+func (m *Map) Summits(pos helper.Position) []helper.Position {
+	if !m.passable(pos) {
+		return nil
+	}
+	var summits []helper.Position
+	for _, i := range m.reachable[pos.Row][pos.Col].Members() {
+		summits = append(summits, m.summits[i])
+	}
+	return summits
+}
+
+// Score returns the sum of the scores of every trailhead
+func (m *Map) Score() int {
+	total := 0
+	for _, pos := range m.trailheads() {
+		total += m.reachable[pos.Row][pos.Col].Count()
+	}
+	return total
+}
+
+// trailheads returns the positions of every trailhead in row-major order
+func (m *Map) trailheads() []helper.Position {
+	var trailheads []helper.Position
+	m.forEachCell(func(pos helper.Position) {
+		if m.height(pos) == m.rules.StartHeight {
+			trailheads = append(trailheads, pos)
+		}
+	})
+	return trailheads
+}
+
+// Counter returns a path counter for the trails on the map. A trailhead's rating is its number of paths.
+func Counter[T any](m *Map, counting helper.Counting[T], method helper.PathMethod) helper.PathCounter[helper.Position, T] {
+	return helper.PathCounter[helper.Position, T]{
+		Next: m.nextSteps,
+		IsSink: func(pos helper.Position) bool {
+			_, ok := m.summitIndex[pos]
+			return ok
+		},
+		Counting: counting,
+		Method:   method,
+	}
+}
+
+// Rating returns the sum of the ratings of every trailhead
+func Rating[T any](m *Map, counting helper.Counting[T], method helper.PathMethod) (T, error) {
+	return Counter(m, counting, method).Count(m.trailheads()...)
+}
+
+// Paths calls visit with each trail from start to a summit, one at a time, until visit returns false.
+// Trails are found lazily by a depth-first walk that never enters cells with no summit in reach.
+// The path slice is reused between calls, so visit must copy it to keep it.
+func (m *Map) Paths(start helper.Position, visit func(path []helper.Position) bool) {
+	if !m.passable(start) {
+		return
+	}
+	path := []helper.Position{start}
+	var walk func(pos helper.Position) bool
+	walk = func(pos helper.Position) bool {
+		if _, ok := m.summitIndex[pos]; ok {
+			return visit(path)
+		}
+		for _, next := range m.nextSteps(pos) {
+			if m.reachable[next.Row][next.Col].IsEmpty() {
+				continue
+			}
+			path = append(path, next)
+			if !walk(next) {
+				return false
+			}
+			path = path[:len(path)-1]
+		}
+		return true
+	}
+	walk(start)
+}
+
+// Render writes the map with only the cells on trails from start shown, and every other cell as ".",
+// like the examples in the puzzle
+func (m *Map) Render(w io.Writer, start helper.Position) error {
+	if !m.passable(start) {
+		return fmt.Errorf("%v is not a passable cell of the map", start)
+	}
+
+	// Mark every cell that lies on some trail from start
+	onTrail := make(map[helper.Position]bool)
+	var mark func(pos helper.Position)
+	mark = func(pos helper.Position) {
+		if onTrail[pos] || m.reachable[pos.Row][pos.Col].IsEmpty() {
+			return
+		}
+		onTrail[pos] = true
+		for _, next := range m.nextSteps(pos) {
+			mark(next)
+		}
+	}
+	mark(start)
+
+	out := bufio.NewWriter(w)
+	for r := range m.heights {
+		for c := range m.heights[r] {
+			if onTrail[helper.Position{Row: r, Col: c}] {
+				fmt.Fprint(out, m.heights[r][c])
+			} else {
+				fmt.Fprint(out, ".")
+			}
+		}
+		fmt.Fprintln(out)
+	}
+	return out.Flush()
+}
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"advent-of-code-2024/runner"
 )
 
 var pow10 = [19]int{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000, 1000000000,
@@ -71,25 +78,74 @@ func applyRules(value int) []int {
 	return []int{value * 2024}
 }
 
-func main() {
+func getInputData(inputPath string) ([]int, error) {
+	content, err := os.ReadFile(inputPath) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	var stones []int
+	for _, field := range strings.Fields(string(content)) {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stone '%s'", field)
+		}
+		stones = append(stones, value)
+	}
+	return stones, nil
+}
 
-	totalIterations := 75
-	results := make([][]int, totalIterations+1)
-	results[0] = []int{773, 79858, 0, 71, 213357, 2937, 1, 3998391}
+// checkEvery is how many stones are expanded between checks for cancellation within an iteration
+const checkEvery = 1 << 20
 
-	fmt.Println("Iteration 0 ->", len(results[0]), "values")
+// blink applies the rules to every stone the given number of times and returns how many stones there are.
+// Only the previous iteration is kept, but the number of stones still grows exponentially, so it checks
+// for cancellation between iterations and every checkEvery stones within one.
+func blink(ctx context.Context, stones []int, iterations int, logger *slog.Logger) (int, error) {
+	logger.Debug("iteration", "iteration", 0, "values", len(stones))
 
 	nextMap := make(map[int][]int)
-	for iteration := 1; iteration <= totalIterations; iteration++ {
+	previousValues := stones
+	for iteration := 1; iteration <= iterations; iteration++ {
 		iterationStart := time.Now()
-		previousValues := &results[iteration-1]
-		currentValues := &results[iteration]
-		for _, previousValue := range *previousValues {
+		currentValues := make([]int, 0, len(previousValues))
+		for i, previousValue := range previousValues {
+			if i%checkEvery == 0 {
+				if err := ctx.Err(); err != nil {
+					return 0, err
+				}
+			}
 			if _, exists := nextMap[previousValue]; !exists {
 				nextMap[previousValue] = applyRules(previousValue)
 			}
-			*currentValues = append(*currentValues, nextMap[previousValue]...)
+			currentValues = append(currentValues, nextMap[previousValue]...)
 		}
-		fmt.Println("Iteration", iteration, "->", len(*currentValues), "values", "( Calculated in ", time.Since(iterationStart), ")")
+		logger.Debug("iteration", "iteration", iteration, "values", len(currentValues), "elapsed", time.Since(iterationStart))
+		previousValues = currentValues
 	}
+	return len(previousValues), nil
+}
+
+func init() {
+	runner.Register(11, 1, part1)
+	runner.Register(11, 2, part2)
+}
+
+func part1(ctx context.Context, inputPath string, logger *slog.Logger) (any, error) {
+	stones, err := getInputData(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return blink(ctx, stones, 25, logger)
+}
+
+func part2(ctx context.Context, inputPath string, logger *slog.Logger) (any, error) {
+	stones, err := getInputData(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return blink(ctx, stones, 75, logger)
+}
+
+func main() {
+	runner.Main()
 }
@@ -0,0 +1,77 @@
+package helper
+
+import "math/bits"
+
+// Bitset is a fixed size set of small non-negative integers, packed 64 to a word
+type Bitset struct {
+	words []uint64
+	size  int
+}
+
+// NewBitset returns an empty set that can hold the integers 0 to size-1
+func NewBitset(size int) Bitset {
+	return Bitset{words: make([]uint64, (size+63)/64), size: size}
+}
+
+// Set adds i to the set
+func (b Bitset) Set(i int) {
+	b.words[i/64] |= 1 << (i % 64)
+}
+
+// Has checks if i is in the set
+func (b Bitset) Has(i int) bool {
+	return i >= 0 && i < b.size && b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Union adds every member of other to the set. Both sets must have the same size.
+func (b Bitset) Union(other Bitset) {
+	for i := range b.words {
+		b.words[i] |= other.words[i]
+	}
+}
+
+// UnionShifted adds i+shift for every member i of other, dropping anything past the set's size
+func (b Bitset) UnionShifted(other Bitset, shift int) {
+	wordShift, bitShift := shift/64, shift%64
+	for i := len(b.words) - 1; i >= wordShift; i-- {
+		word := other.words[i-wordShift] << bitShift
+		if bitShift > 0 && i-wordShift-1 >= 0 {
+			word |= other.words[i-wordShift-1] >> (64 - bitShift)
+		}
+		b.words[i] |= word
+	}
+	if extra := b.size % 64; extra != 0 {
+		b.words[len(b.words)-1] &= 1<<extra - 1
+	}
+}
+
+// Count returns the number of members in the set
+func (b Bitset) Count() int {
+	count := 0
+	for _, word := range b.words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// IsEmpty checks if the set has no members
+func (b Bitset) IsEmpty() bool {
+	for _, word := range b.words {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Members returns the members of the set in ascending order
+func (b Bitset) Members() []int {
+	var members []int
+	for i, word := range b.words {
+		for word != 0 {
+			members = append(members, i*64+bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+	return members
+}
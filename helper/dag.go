@@ -0,0 +1,290 @@
+package helper
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Counting is the arithmetic used to count paths, so counts can be checked, wrapped or unbounded
+type Counting[T any] interface {
+	Zero() T
+	One() T
+	Add(a, b T) (T, error)
+}
+
+// Uint64Counting counts with uint64 and fails if a count overflows
+type Uint64Counting struct{}
+
+func (Uint64Counting) Zero() uint64 { return 0 }
+func (Uint64Counting) One() uint64  { return 1 }
+
+func (Uint64Counting) Add(a, b uint64) (uint64, error) {
+	if a > math.MaxUint64-b {
+		return 0, fmt.Errorf("path count overflows uint64, use modular or big counting")
+	}
+	return a + b, nil
+}
+
+// ModularCounting counts modulo Modulus, which must be at least 1
+type ModularCounting struct {
+	Modulus uint64
+}
+
+func (ModularCounting) Zero() uint64 { return 0 }
+
+func (c ModularCounting) One() uint64 { return 1 % c.Modulus }
+
+func (c ModularCounting) Add(a, b uint64) (uint64, error) {
+	// a and b are already reduced, so a+b only needs one subtraction, done without overflowing
+	if a >= c.Modulus-b {
+		return a - (c.Modulus - b), nil
+	}
+	return a + b, nil
+}
+
+// BigCounting counts exactly with big.Int. Add returns a new value and never modifies its operands.
+type BigCounting struct{}
+
+func (BigCounting) Zero() *big.Int { return new(big.Int) }
+func (BigCounting) One() *big.Int  { return big.NewInt(1) }
+
+func (BigCounting) Add(a, b *big.Int) (*big.Int, error) {
+	return new(big.Int).Add(a, b), nil
+}
+
+// PathMethod selects how PathCounter walks the graph
+type PathMethod int
+
+const (
+	TopologicalOrder PathMethod = iota // Kahn's algorithm over the nodes reachable from the sources, without recursion
+	MemoisedDFS                        // Depth-first search caching each node's count, only visiting what it needs
+)
+
+// ParsePathMethod converts a method name (topological or dfs) to a PathMethod
+func ParsePathMethod(name string) (PathMethod, error) {
+	switch name {
+	case "topological":
+		return TopologicalOrder, nil
+	case "dfs":
+		return MemoisedDFS, nil
+	}
+	return TopologicalOrder, fmt.Errorf("unknown path counting method '%s'", name)
+}
+
+// CycleError reports that a graph expected to be acyclic has a cycle
+type CycleError[N comparable] struct {
+	Cycle []N // Nodes in edge order, with the first node repeated at the end
+}
+
+func (e CycleError[N]) Error() string {
+	nodes := make([]string, len(e.Cycle))
+	for i, node := range e.Cycle {
+		nodes[i] = fmt.Sprint(node)
+	}
+	return "graph has a cycle: " + strings.Join(nodes, " -> ")
+}
+
+// PathCounter counts the paths in a directed acyclic graph given by its neighbour function.
+// A path runs from a source to any sink; it may pass through other sinks, and each sink it reaches counts as a
+// separate path, so a sink with no neighbours is simply where paths end.
+type PathCounter[N comparable, T any] struct {
+	Next     func(node N) []N // The nodes an edge leads to from node
+	IsSink   func(node N) bool
+	Counting Counting[T]
+	Method   PathMethod
+}
+
+// Count returns the total number of paths from the sources to the sinks
+func (p PathCounter[N, T]) Count(sources ...N) (T, error) {
+	total := p.Counting.Zero()
+	counts, err := p.CountFrom(sources...)
+	if err != nil {
+		return total, err
+	}
+	for _, source := range sources {
+		if total, err = p.Counting.Add(total, counts[source]); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// CountFrom returns the number of paths to the sinks from every node reachable from the sources.
+// It returns a CycleError if any of those nodes is on a cycle.
+func (p PathCounter[N, T]) CountFrom(sources ...N) (map[N]T, error) {
+	if p.Method == MemoisedDFS {
+		return p.countDFS(sources)
+	}
+	return p.countTopological(sources)
+}
+
+// countTopological works backwards through a topological order, so every node's neighbours
+// are counted before the node itself
+func (p PathCounter[N, T]) countTopological(sources []N) (map[N]T, error) {
+	order, err := TopologicalSort(Reachable(sources, p.Next), p.Next)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[N]T, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		if counts[order[i]], err = p.countNode(order[i], counts); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+func (p PathCounter[N, T]) countDFS(sources []N) (map[N]T, error) {
+	counts := make(map[N]T)
+	var stack []N               // The nodes being visited, each a neighbour of the one before
+	visiting := make(map[N]int) // Position of each node being visited on the stack
+	var visit func(node N) error
+	visit = func(node N) error {
+		if _, ok := counts[node]; ok {
+			return nil
+		}
+		if at, ok := visiting[node]; ok {
+			return CycleError[N]{Cycle: append(append([]N{}, stack[at:]...), node)}
+		}
+		visiting[node] = len(stack)
+		stack = append(stack, node)
+		for _, next := range p.Next(node) {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		delete(visiting, node)
+		stack = stack[:len(stack)-1]
+
+		count, err := p.countNode(node, counts)
+		counts[node] = count
+		return err
+	}
+
+	for _, source := range sources {
+		if err := visit(source); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// countNode adds up the paths from a node whose neighbours have all been counted
+func (p PathCounter[N, T]) countNode(node N, counts map[N]T) (T, error) {
+	count := p.Counting.Zero()
+	if p.IsSink(node) {
+		count = p.Counting.One()
+	}
+	var err error
+	for _, next := range p.Next(node) {
+		if count, err = p.Counting.Add(count, counts[next]); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// Reachable returns the sources and every node reachable from them, each once, in the order they're found
+func Reachable[N comparable](sources []N, next func(node N) []N) []N {
+	seen := make(map[N]bool)
+	var nodes []N
+	for _, source := range sources {
+		if !seen[source] {
+			seen[source] = true
+			nodes = append(nodes, source)
+		}
+	}
+	for i := 0; i < len(nodes); i++ {
+		for _, n := range next(nodes[i]) {
+			if !seen[n] {
+				seen[n] = true
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	return nodes
+}
+
+// TopologicalSort orders the nodes so every edge between them goes from an earlier node to a later one,
+// using Kahn's algorithm. Edges to nodes not in the list are ignored. It returns a CycleError if the nodes have a cycle.
+func TopologicalSort[N comparable](nodes []N, next func(node N) []N) ([]N, error) {
+	inDegree := make(map[N]int, len(nodes))
+	for _, node := range nodes {
+		inDegree[node] = 0
+	}
+	for _, node := range nodes {
+		for _, n := range next(node) {
+			if _, ok := inDegree[n]; ok {
+				inDegree[n]++
+			}
+		}
+	}
+
+	var queue []N
+	for _, node := range nodes {
+		if inDegree[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+	order := make([]N, 0, len(nodes))
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		order = append(order, node)
+		for _, n := range next(node) {
+			if _, ok := inDegree[n]; !ok {
+				continue
+			}
+			inDegree[n]--
+			if inDegree[n] == 0 {
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	if len(order) < len(nodes) {
+		return nil, CycleError[N]{Cycle: findCycle(nodes, next, inDegree)}
+	}
+	return order, nil
+}
+
+// findCycle returns a cycle among the nodes Kahn's algorithm couldn't order, which are left with a positive in-degree.
+// Each of them has a predecessor that wasn't ordered either, so walking back through predecessors must repeat a node.
+func findCycle[N comparable](nodes []N, next func(node N) []N, inDegree map[N]int) []N {
+	var start N
+	found := false
+	predecessor := make(map[N]N)
+	for _, node := range nodes {
+		if inDegree[node] == 0 {
+			continue
+		}
+		if !found {
+			start, found = node, true
+		}
+		for _, n := range next(node) {
+			if inDegree[n] > 0 {
+				predecessor[n] = node
+			}
+		}
+	}
+
+	var walk []N
+	seen := make(map[N]int)
+	for node := start; ; node = predecessor[node] {
+		if at, ok := seen[node]; ok {
+			walk = walk[at:]
+			break
+		}
+		seen[node] = len(walk)
+		walk = append(walk, node)
+	}
+
+	// The walk followed edges backwards, so reverse it and close the loop
+	cycle := make([]N, 0, len(walk)+1)
+	for i := len(walk) - 1; i >= 0; i-- {
+		cycle = append(cycle, walk[i])
+	}
+	return append(cycle, cycle[0])
+}
@@ -0,0 +1,176 @@
+package helper
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// graph is a neighbour function for a graph given as adjacency lists
+func graph[N comparable](edges map[N][]N) func(node N) []N {
+	return func(node N) []N { return edges[node] }
+}
+
+// diamonds is a chain of n diamonds, each doubling the paths from node 0 to node 3n
+func diamonds(n int) func(node int) []int {
+	return func(node int) []int {
+		switch {
+		case node >= 3*n:
+			return nil
+		case node%3 == 0:
+			return []int{node + 1, node + 2}
+		default:
+			return []int{node - node%3 + 3}
+		}
+	}
+}
+
+var methods = []struct {
+	name   string
+	method PathMethod
+}{
+	{"topological", TopologicalOrder},
+	{"dfs", MemoisedDFS},
+}
+
+// countAll counts the paths from source with every method and counting mode, converting the counts to big.Int
+func countAll[N comparable](t *testing.T, next func(node N) []N, isSink func(node N) bool, modulus uint64, source N) map[string]map[N]*big.Int {
+	t.Helper()
+	counts := make(map[string]map[N]*big.Int)
+	for _, m := range methods {
+		uint64Counts, err := PathCounter[N, uint64]{next, isSink, Uint64Counting{}, m.method}.CountFrom(source)
+		if err != nil {
+			t.Fatalf("%s uint64: %v", m.name, err)
+		}
+		modularCounts, err := PathCounter[N, uint64]{next, isSink, ModularCounting{Modulus: modulus}, m.method}.CountFrom(source)
+		if err != nil {
+			t.Fatalf("%s modular: %v", m.name, err)
+		}
+		bigCounts, err := PathCounter[N, *big.Int]{next, isSink, BigCounting{}, m.method}.CountFrom(source)
+		if err != nil {
+			t.Fatalf("%s big: %v", m.name, err)
+		}
+
+		toBig := func(counts map[N]uint64) map[N]*big.Int {
+			converted := make(map[N]*big.Int, len(counts))
+			for node, count := range counts {
+				converted[node] = new(big.Int).SetUint64(count)
+			}
+			return converted
+		}
+		counts[m.name+" uint64"] = toBig(uint64Counts)
+		counts[m.name+" modular"] = toBig(modularCounts)
+		counts[m.name+" big"] = bigCounts
+	}
+	return counts
+}
+
+func TestPathCounterMethodsAgree(t *testing.T) {
+	// e is a sink that paths can carry on from, so a path through it to f counts twice
+	next := graph(map[string][]string{
+		"a": {"b", "c"},
+		"b": {"d", "f"},
+		"c": {"d"},
+		"d": {"e", "f"},
+		"e": {"f"},
+	})
+	isSink := func(node string) bool { return node == "e" || node == "f" }
+	want := map[string]int64{"a": 7, "b": 4, "c": 3, "d": 3, "e": 2, "f": 1}
+	const modulus = 5
+
+	counts := countAll(t, next, isSink, modulus, "a")
+	for name, got := range counts {
+		if len(got) != len(want) {
+			t.Errorf("%s: counted %d nodes, want %d", name, len(got), len(want))
+		}
+		for node, count := range want {
+			expected := big.NewInt(count)
+			if name == "topological modular" || name == "dfs modular" {
+				expected.Mod(expected, big.NewInt(modulus))
+			}
+			if got[node] == nil || got[node].Cmp(expected) != 0 {
+				t.Errorf("%s: got %v paths from %s, want %v", name, got[node], node, expected)
+			}
+		}
+	}
+}
+
+func TestPathCounterOverflow(t *testing.T) {
+	next := diamonds(64)
+	isSink := func(node int) bool { return node == 3*64 }
+	want := new(big.Int).Lsh(big.NewInt(1), 64)
+
+	for _, m := range methods {
+		t.Run(m.name, func(t *testing.T) {
+			if _, err := (PathCounter[int, uint64]{next, isSink, Uint64Counting{}, m.method}).Count(0); err == nil {
+				t.Error("counted 2^64 paths in a uint64 without an error")
+			}
+
+			got, err := PathCounter[int, *big.Int]{next, isSink, BigCounting{}, m.method}.Count(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Cmp(want) != 0 {
+				t.Errorf("got %v paths, want %v", got, want)
+			}
+
+			const modulus = 1_000_000_007
+			gotModular, err := PathCounter[int, uint64]{next, isSink, ModularCounting{Modulus: modulus}, m.method}.Count(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wantModular := new(big.Int).Mod(want, big.NewInt(modulus)).Uint64(); gotModular != wantModular {
+				t.Errorf("got %d paths modulo %d, want %d", gotModular, modulus, wantModular)
+			}
+		})
+	}
+}
+
+// checkCycle fails the test unless err is a CycleError whose nodes are joined by edges and end where they start
+func checkCycle[N comparable](t *testing.T, err error, next func(node N) []N) {
+	t.Helper()
+	var cycleErr CycleError[N]
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("got error %v, want a CycleError", err)
+	}
+	cycle := cycleErr.Cycle
+	if len(cycle) < 2 || cycle[0] != cycle[len(cycle)-1] {
+		t.Fatalf("cycle %v doesn't end where it starts", cycle)
+	}
+	for i := 1; i < len(cycle); i++ {
+		edge := false
+		for _, n := range next(cycle[i-1]) {
+			edge = edge || n == cycle[i]
+		}
+		if !edge {
+			t.Errorf("cycle %v has no edge from %v to %v", cycle, cycle[i-1], cycle[i])
+		}
+	}
+}
+
+func TestCycleError(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges map[string][]string
+	}{
+		{"self loop", map[string][]string{"a": {"b"}, "b": {"b"}}},
+		{"cycle after a branch", map[string][]string{"a": {"b", "e"}, "b": {"c"}, "c": {"d"}, "d": {"b"}}},
+		// x and y can't be ordered either, but aren't on the cycle
+		{"nodes downstream of a cycle", map[string][]string{"a": {"b"}, "b": {"c", "x"}, "c": {"b"}, "x": {"y"}, "y": {"z"}}},
+		{"two cycles", map[string][]string{"a": {"b", "d"}, "b": {"c"}, "c": {"b"}, "d": {"e"}, "e": {"f"}, "f": {"d"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := graph(tt.edges)
+			_, err := TopologicalSort(Reachable([]string{"a"}, next), next)
+			checkCycle(t, err, next)
+
+			isSink := func(node string) bool { return len(next(node)) == 0 }
+			for _, m := range methods {
+				_, err := PathCounter[string, uint64]{next, isSink, Uint64Counting{}, m.method}.Count("a")
+				checkCycle(t, err, next)
+			}
+		})
+	}
+}
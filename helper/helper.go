@@ -45,6 +45,16 @@ func ReadInputToGrid(filename string) Grid {
 }
 
 func ReadInputToInt2DArray(filename string) [][]int {
+	return readDigitGrid(filename, nil)
+}
+
+// ReadInputToInt2DArrayWithBlanks is like ReadInputToInt2DArray but reads '.' cells as blank instead of failing
+func ReadInputToInt2DArrayWithBlanks(filename string, blank int) [][]int {
+	return readDigitGrid(filename, &blank)
+}
+
+// readDigitGrid reads a grid of single digits, with '.' cells read as blank if it is set
+func readDigitGrid(filename string, blank *int) [][]int {
 	file, err := os.Open(filename)
 	if err != nil {
 		log.Fatal(err)
@@ -57,6 +67,10 @@ func ReadInputToInt2DArray(filename string) [][]int {
 		line := scanner.Text()
 		var nums []int
 		for _, c := range line {
+			if c == '.' && blank != nil {
+				nums = append(nums, *blank)
+				continue
+			}
 			// Convert rune to string then to int
 			num, err := strconv.Atoi(string(c))
 			if err != nil {
@@ -214,8 +228,8 @@ func (p Position) IsCollinearWith(p1, p2 Position, tolerance float64) bool {
 func StringsToInts(strings []string) ([]int, error) {
 	var numbers []int
 	for _, s := range strings {
-		var num int
-		if _, err := fmt.Sscanf(s, "%d", &num); err != nil {
+		num, err := strconv.Atoi(s)
+		if err != nil {
 			return nil, fmt.Errorf("failed to parse number '%s': %v", s, err)
 		}
 		numbers = append(numbers, num)
@@ -308,25 +322,6 @@ func CanFit(start, size int, diskMap []string) bool {
 	return true
 }
 
-// InitLogger initializes the logger and returns a file pointer
-func InitLogger(filePath string) (*os.File, error) {
-	// If the file exists, remove it
-	if _, err := os.Stat(filePath); err == nil {
-		if err := os.Remove(filePath); err != nil {
-			return nil, err
-		}
-	}
-
-	// Create/open a new file for logging
-	logFile, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) // #nosec G304
-	if err != nil {
-		return nil, err
-	}
-
-	log.SetOutput(logFile)
-	return logFile, nil
-}
-
 // ParseDiskMap parses the input string into a disk map representation
 func ParseDiskMap(input string) []string {
 	var diskMap []string
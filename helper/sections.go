@@ -0,0 +1,90 @@
+package helper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Line is a line of input together with its 1-based line number in the file
+type Line struct {
+	Number int
+	Text   string
+}
+
+// LineError reports a problem with a specific line of input
+type LineError struct {
+	Line Line
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v: '%s'", e.Line.Number, e.Err, e.Line.Text)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// NewLineError wraps a message about a line of input as a LineError
+func NewLineError(line Line, format string, args ...any) *LineError {
+	return &LineError{Line: line, Err: fmt.Errorf(format, args...)}
+}
+
+// ReadTwoSections reads a file made of two non-empty sections separated by a single blank line
+func ReadTwoSections(filename string) ([]Line, []Line, error) {
+	file, err := os.Open(filename) // #nosec G304
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	return ParseTwoSections(file)
+}
+
+// ParseTwoSections splits input into two non-empty sections separated by a single blank line.
+// Blank lines at the very end of the input are ignored; any other extra blank line is an error.
+func ParseTwoSections(r io.Reader) ([]Line, []Line, error) {
+	var sections [2][]Line
+	section := 0
+	var pendingBlank *Line
+
+	scanner := bufio.NewScanner(r)
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := Line{Number: number, Text: scanner.Text()}
+		if line.Text == "" {
+			if pendingBlank == nil {
+				pendingBlank = &line
+			}
+			continue
+		}
+
+		// A blank line before this one either separates the sections or is out of place
+		if pendingBlank != nil {
+			switch {
+			case section == 0 && len(sections[0]) == 0:
+				return nil, nil, NewLineError(*pendingBlank, "blank line before the first section")
+			case section == 1:
+				return nil, nil, NewLineError(*pendingBlank, "unexpected blank line, expected only two sections")
+			case pendingBlank.Number != number-1:
+				return nil, nil, NewLineError(*pendingBlank, "sections must be separated by a single blank line")
+			}
+			section = 1
+			pendingBlank = nil
+		}
+		sections[section] = append(sections[section], line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(sections[0]) == 0 {
+		return nil, nil, fmt.Errorf("input is empty")
+	}
+	if len(sections[1]) == 0 {
+		return nil, nil, fmt.Errorf("input has no second section after line %d", sections[0][len(sections[0])-1].Number)
+	}
+	return sections[0], sections[1], nil
+}
@@ -0,0 +1,167 @@
+package helper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Wildcard marks a cell of a Shape that matches anything
+const Wildcard = "."
+
+// Shape is a small template grid matched against a Grid, where Wildcard cells match any value
+type Shape [][]string
+
+// ShapeMatch is one occurrence of a shape in a grid
+type ShapeMatch struct {
+	Origin    Position   // Grid position of the top left cell of the matching variant
+	Variant   Shape      // The rotation or reflection of the shape that matched
+	Positions []Position // Grid positions of the variant's non-wildcard cells, in row-major order
+}
+
+// ParseShape parses a shape written as rows separated by "/" or newlines, e.g. "M.S / .A. / M.S"
+func ParseShape(s string) (Shape, error) {
+	rows := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '/' || r == '\n'
+	})
+
+	var shape Shape
+	for _, row := range rows {
+		row = strings.TrimSpace(row)
+		if row == "" {
+			continue
+		}
+		shape = append(shape, strings.Split(row, ""))
+	}
+	if len(shape) == 0 {
+		return nil, fmt.Errorf("shape '%s' is empty", s)
+	}
+	for _, row := range shape {
+		if len(row) != len(shape[0]) {
+			return nil, fmt.Errorf("shape '%s' is not rectangular", s)
+		}
+	}
+	return shape, nil
+}
+
+// MustParseShape is like ParseShape but panics if the shape is invalid, for shapes written in code
+func MustParseShape(s string) Shape {
+	shape, err := ParseShape(s)
+	if err != nil {
+		panic(err)
+	}
+	return shape
+}
+
+func (s Shape) String() string {
+	rows := make([]string, len(s))
+	for i, row := range s {
+		rows[i] = strings.Join(row, "")
+	}
+	return strings.Join(rows, "/")
+}
+
+// Rotate returns the shape turned 90 degrees clockwise
+func (s Shape) Rotate() Shape {
+	height, width := len(s), len(s[0])
+	rotated := make(Shape, width)
+	for row := range rotated {
+		rotated[row] = make([]string, height)
+		for col := range rotated[row] {
+			rotated[row][col] = s[height-1-col][row]
+		}
+	}
+	return rotated
+}
+
+// Reflect returns the shape mirrored left to right
+func (s Shape) Reflect() Shape {
+	reflected := make(Shape, len(s))
+	for row := range s {
+		reflected[row] = make([]string, len(s[row]))
+		for col := range s[row] {
+			reflected[row][col] = s[row][len(s[row])-1-col]
+		}
+	}
+	return reflected
+}
+
+// Variants returns the distinct rotations and reflections of the shape, starting with the shape itself
+func (s Shape) Variants() []Shape {
+	var variants []Shape
+	seen := make(map[string]bool)
+	for _, start := range []Shape{s, s.Reflect()} {
+		variant := start
+		for i := 0; i < 4; i++ {
+			if !seen[variant.String()] {
+				seen[variant.String()] = true
+				variants = append(variants, variant)
+			}
+			variant = variant.Rotate()
+		}
+	}
+	return variants
+}
+
+// MatchesAt checks if the shape matches the grid with its top left cell at origin
+func (g Grid) MatchesAt(shape Shape, origin Position) bool {
+	for row := range shape {
+		for col, want := range shape[row] {
+			if want == Wildcard {
+				continue
+			}
+			pos := origin.Add(row, col)
+			if !g.IsInBounds(pos) || g.Get(pos) != want {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FindShape returns every occurrence of the shape in the grid under any rotation or reflection
+func (g Grid) FindShape(shape Shape) []ShapeMatch {
+	return g.FindShapes(shape.Variants()...)
+}
+
+// FindShapes returns every occurrence of any of the shapes in the grid, exactly as given.
+// Occurrences covering the same grid cells are only reported once, for the first shape that matched,
+// so symmetric shapes and overlapping variants aren't counted twice.
+func (g Grid) FindShapes(shapes ...Shape) []ShapeMatch {
+	var matches []ShapeMatch
+	seen := make(map[string]bool)
+	for _, shape := range shapes {
+		for row := 0; row+len(shape) <= g.Height(); row++ {
+			for col := 0; col+len(shape[0]) <= g.Width(); col++ {
+				origin := Position{Row: row, Col: col}
+				if !g.MatchesAt(shape, origin) {
+					continue
+				}
+
+				var positions []Position
+				for r := range shape {
+					for c := range shape[r] {
+						if shape[r][c] != Wildcard {
+							positions = append(positions, origin.Add(r, c))
+						}
+					}
+				}
+				key := fmt.Sprint(positions)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				matches = append(matches, ShapeMatch{Origin: origin, Variant: shape, Positions: positions})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i].Origin, matches[j].Origin
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		return a.Col < b.Col
+	})
+	return matches
+}
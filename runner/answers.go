@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AnswersFile is the name of the file in each day's directory holding its accepted answers
+const AnswersFile = "answers.json"
+
+// Answers are a day's accepted answers, empty for parts not yet solved
+type Answers struct {
+	Part1 string `json:"part1,omitempty"`
+	Part2 string `json:"part2,omitempty"`
+}
+
+// Get returns the answer to a part, or false if there isn't one
+func (a Answers) Get(part int) (string, bool) {
+	answer := ""
+	switch part {
+	case 1:
+		answer = a.Part1
+	case 2:
+		answer = a.Part2
+	}
+	return answer, answer != ""
+}
+
+// Set records the answer to a part
+func (a *Answers) Set(part int, answer string) {
+	switch part {
+	case 1:
+		a.Part1 = answer
+	case 2:
+		a.Part2 = answer
+	}
+}
+
+// LoadAnswers reads the answers in a day's directory. A missing file means no answers yet.
+func LoadAnswers(dayDir string) (Answers, error) {
+	var answers Answers
+	data, err := os.ReadFile(filepath.Join(dayDir, AnswersFile)) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return answers, nil
+	}
+	if err != nil {
+		return answers, err
+	}
+	if err := json.Unmarshal(data, &answers); err != nil {
+		return answers, fmt.Errorf("reading %s: %v", filepath.Join(dayDir, AnswersFile), err)
+	}
+	return answers, nil
+}
+
+// SaveAnswers writes the answers to a day's directory
+func SaveAnswers(dayDir string, answers Answers) error {
+	data, err := json.MarshalIndent(answers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dayDir, AnswersFile), append(data, '\n'), 0o644) // #nosec G306
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Logging flags shared by every day
+var (
+	verboseFlag   = flag.Bool("v", false, "log at debug level, including large values such as parsed inputs")
+	logFileFlag   = flag.String("log-file", "", "append logs to this file instead of standard error")
+	logFormatFlag = flag.String("log-format", "text", "log format: text or json")
+)
+
+// NewLogger builds a logger writing to w at the given level, as text or json
+func NewLogger(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	options := &slog.HandlerOptions{Level: level}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	}
+	return nil, fmt.Errorf("unknown log format '%s', expected text or json", format)
+}
+
+// DiscardLogger returns a logger that drops everything, for calling solvers from tests and benchmarks
+func DiscardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+// loggerFromFlags builds the logger described by -v, -log-file and -log-format. The returned function
+// closes the log file, if there is one.
+func loggerFromFlags() (*slog.Logger, func() error, error) {
+	level := slog.LevelInfo
+	if *verboseFlag {
+		level = slog.LevelDebug
+	}
+	var w io.Writer = os.Stderr
+	closeLog := func() error { return nil }
+	if *logFileFlag != "" {
+		file, err := os.OpenFile(*logFileFlag, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) // #nosec G302 G304
+		if err != nil {
+			return nil, nil, err
+		}
+		w, closeLog = file, file.Close
+	}
+	logger, err := NewLogger(w, level, *logFormatFlag)
+	if err != nil {
+		closeLog()
+		return nil, nil, err
+	}
+	return logger, closeLog, nil
+}
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// Result is the outcome of running one part
+type Result struct {
+	Day      int           `json:"day"`
+	Part     int           `json:"part"`
+	Answer   string        `json:"answer"`
+	Duration time.Duration `json:"-"`
+	Error    string        `json:"error,omitempty"`
+	TimedOut bool          `json:"timed_out,omitempty"` // The part ran out of time, and Error says how long it had
+}
+
+// MarshalJSON writes the duration in milliseconds, which dashboards can plot without knowing Go's units
+func (r Result) MarshalJSON() ([]byte, error) {
+	type plain Result
+	return json.Marshal(struct {
+		plain
+		DurationMS float64 `json:"duration_ms"`
+	}{plain(r), durationMS(r.Duration)})
+}
+
+// UnmarshalJSON reads a result written by MarshalJSON
+func (r *Result) UnmarshalJSON(data []byte) error {
+	type plain Result
+	var decoded struct {
+		plain
+		DurationMS float64 `json:"duration_ms"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = Result(decoded.plain)
+	r.Duration = time.Duration(decoded.DurationMS * float64(time.Millisecond))
+	return nil
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Solve runs a part on an input file and times it, logging with the day and part attached.
+// With a timeout, the part is reported as timed out once it has run that long, even if the solver
+// never checks its context and is left running.
+func Solve(ctx context.Context, part Part, inputPath string, logger *slog.Logger, timeout time.Duration) Result {
+	result := Result{Day: part.Day, Part: part.Part}
+	logger = logger.With("day", part.Day, "part", part.Part)
+	logger.Debug("solving", "input", inputPath, "timeout", timeout)
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type solution struct {
+		answer any
+		err    error
+	}
+	done := make(chan solution, 1)
+	start := time.Now()
+	go func() {
+		answer, err := part.Solver(ctx, inputPath, logger)
+		done <- solution{answer, err}
+	}()
+
+	var answer any
+	var err error
+	select {
+	case solved := <-done:
+		answer, err = solved.answer, solved.err
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result.Duration = time.Since(start)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		result.TimedOut = true
+		result.Error = fmt.Sprintf("timed out after %s", timeout)
+	case err != nil:
+		result.Error = err.Error()
+	default:
+		result.Answer = fmt.Sprint(answer)
+	}
+	if result.Error != "" {
+		logger.Debug("failed", "duration", result.Duration, "error", result.Error)
+	} else {
+		logger.Debug("solved", "duration", result.Duration, "answer", result.Answer)
+	}
+	return result
+}
+
+// Format is how results are written
+type Format int
+
+const (
+	Text Format = iota // "Day N Part P: answer" lines, like the days always printed
+	JSON               // A JSON array of results
+	CSV                // A header row, then a row per result
+)
+
+// ParseFormat converts a format name (text, json or csv) to a Format
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "text":
+		return Text, nil
+	case "json":
+		return JSON, nil
+	case "csv":
+		return CSV, nil
+	}
+	return Text, fmt.Errorf("unknown output format '%s', expected text, json or csv", name)
+}
+
+// WriteResults writes results in a format. In text, failed parts are reported on errOut instead.
+func WriteResults(w, errOut io.Writer, results []Result, format Format) error {
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if results == nil {
+			results = []Result{}
+		}
+		return encoder.Encode(results)
+	case CSV:
+		out := csv.NewWriter(w)
+		_ = out.Write([]string{"day", "part", "answer", "duration_ms", "error"})
+		for _, r := range results {
+			_ = out.Write([]string{
+				strconv.Itoa(r.Day),
+				strconv.Itoa(r.Part),
+				r.Answer,
+				strconv.FormatFloat(durationMS(r.Duration), 'f', 3, 64),
+				r.Error,
+			})
+		}
+		out.Flush()
+		return out.Error()
+	}
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Fprintf(errOut, "Day %d Part %d: %s\n", r.Day, r.Part, r.Error)
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "Day %d Part %d: %s\n", r.Day, r.Part, r.Answer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+)
+
+// Solver solves one part of a puzzle, given the path of the input file and a logger already tagged
+// with the day and part. Long-running solvers should stop with ctx.Err() once ctx is done.
+type Solver func(ctx context.Context, inputPath string, logger *slog.Logger) (any, error)
+
+// Part is a registered solver for one part of a day's puzzle
+type Part struct {
+	Day    int
+	Part   int
+	Solver Solver
+}
+
+var registry = make(map[[2]int]Solver)
+
+// Flags shared by every day. Days add their own flags to the same command line.
+var (
+	inputFlag   = flag.String("input", "", "puzzle input file (default: the day's input.txt)")
+	partFlag    = flag.Int("part", 0, "only run this part (default: every part)")
+	formatFlag  = flag.String("format", "text", "output format: text, json or csv")
+	timeoutFlag = flag.Duration("timeout", 0, "give up on a part after this long, e.g. 30s (default: no limit)")
+)
+
+// Register adds the solver for a part of a day's puzzle, usually from the day's init function.
+// It panics if the part already has a solver.
+func Register(day, part int, solver Solver) {
+	key := [2]int{day, part}
+	if _, ok := registry[key]; ok {
+		panic(fmt.Sprintf("day %d part %d is already registered", day, part))
+	}
+	registry[key] = solver
+}
+
+// Parts returns every registered part, ordered by day then part
+func Parts() []Part {
+	parts := make([]Part, 0, len(registry))
+	for key, solver := range registry {
+		parts = append(parts, Part{Day: key[0], Part: key[1], Solver: solver})
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		if parts[i].Day != parts[j].Day {
+			return parts[i].Day < parts[j].Day
+		}
+		return parts[i].Part < parts[j].Part
+	})
+	return parts
+}
+
+// DayDir returns the name of a day's directory, e.g. "day07"
+func DayDir(day int) string {
+	return fmt.Sprintf("day%02d", day)
+}
+
+// InputPath returns the path of a day's input file, whether running from the repository root or the day's directory
+func InputPath(day int) string {
+	if cwd, err := os.Getwd(); err == nil && filepath.Base(cwd) == DayDir(day) {
+		return "input.txt"
+	}
+	return filepath.Join(DayDir(day), "input.txt")
+}
+
+// Input returns the input file given with -input, or the day's input.txt
+func Input(day int) string {
+	if *inputFlag != "" {
+		return *inputFlag
+	}
+	return InputPath(day)
+}
+
+// Main runs the registered solvers and writes their results in the -format given. It parses the command line
+// if the day hasn't already, so days can define their own flags and check them before calling it.
+// Each part gets -timeout to finish, and an interrupt cancels the part running. It exits with status 1
+// if any part failed.
+func Main() {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	format, err := ParseFormat(*formatFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logger, closeLog, err := loggerFromFlags()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var results []Result
+	failed := false
+	for _, part := range Parts() {
+		if *partFlag != 0 && part.Part != *partFlag {
+			continue
+		}
+		result := Solve(ctx, part, Input(part.Day), logger, *timeoutFlag)
+		failed = failed || result.Error != ""
+		results = append(results, result)
+	}
+	if err := closeLog(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := WriteResults(os.Stdout, os.Stderr, results, format); err != nil {
+		log.Fatal(err)
+	}
+	if failed {
+		stop()
+		os.Exit(1)
+	}
+}